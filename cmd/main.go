@@ -5,12 +5,15 @@ import (
 	"time"
 
 	"CinemaBooking/config"
+	"CinemaBooking/pkg/db"
 	"CinemaBooking/pkg/routes"
+	"CinemaBooking/pkg/services"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "CinemaBooking/docs" // swagger docs
+	_ "time/tzdata"        // часовые пояса кинотеатров без системной tzdata в контейнере
 )
 
 func main() {
@@ -21,8 +24,24 @@ func main() {
 	time.Sleep(5 * time.Second)
 
 	// Подключаемся к БД
-	// db.InitDB()
-	// defer db.CloseDB()
+	db.InitDB()
+	defer db.CloseDB()
+
+	// Платёжный провайдер
+	if err := services.InitPayments(config.GetPaymentProvider(), config.GetPaymentGatewayURL(), config.GetPaymentWebhookSecret()); err != nil {
+		log.Fatalf("Ошибка настройки платежей: %v", err)
+	}
+
+	// Перенос балансов в журнал проводок
+	if err := services.InitLedger(); err != nil {
+		log.Fatalf("Ошибка переноса балансов в журнал проводок: %v", err)
+	}
+
+	// Фоновое снятие просроченных броней, отправка уведомлений, сверка платежей и журнала
+	services.StartHoldSweeper(config.GetHoldSweepInterval())
+	services.StartNotificationDispatcher(config.GetNotifyInterval())
+	services.StartPaymentReconciler(config.GetPaymentReconcileInterval())
+	services.StartLedgerChecker(config.GetLedgerCheckInterval())
 
 	// Создаём роутер
 	r := routes.SetupRouter()
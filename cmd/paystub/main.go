@@ -0,0 +1,32 @@
+// Локальная заглушка платёжного шлюза для разработки: платежи хранятся в памяти,
+// подтверждение успешно, кроме сумм с 13 копейками. Уведомления о смене статуса уходят
+// на PAYMENT_WEBHOOK_URL с подписью секретом PAYMENT_WEBHOOK_SECRET. Сервис переключается на неё через PAYMENT_PROVIDER=http.
+//
+//	go run ./cmd/paystub -addr :8090
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"CinemaBooking/config"
+	"CinemaBooking/pkg/payments"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "адрес, на котором слушает заглушка")
+	flag.Parse()
+
+	webhookURL := config.GetPaymentWebhookURL()
+	secret := config.GetPaymentWebhookSecret()
+	if secret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET не задан: уведомления нечем подписать")
+	}
+	standIn := payments.NewStandIn(webhookURL, secret)
+
+	log.Printf("Заглушка платёжного шлюза на %s, уведомления на %s", *addr, webhookURL)
+	if err := http.ListenAndServe(*addr, standIn.Handler()); err != nil {
+		log.Fatalf("Ошибка запуска заглушки: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Нагрузочная проверка выдачи мест: много одновременных попыток удержать одно и то же место.
+// Ожидается ровно один победитель, остальные попытки должны получить services.ErrSeatTaken.
+//
+//	go run ./cmd/seatstress -session 1 -row 1 -seat 1 -users 1,2,3 -workers 50
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"CinemaBooking/config"
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/services"
+)
+
+func main() {
+	sessionID := flag.Uint("session", 0, "ID сеанса")
+	row := flag.Uint("row", 1, "ряд")
+	seat := flag.Uint("seat", 1, "место")
+	usersFlag := flag.String("users", "", "ID пользователей через запятую")
+	workers := flag.Int("workers", 50, "число одновременных попыток")
+	flag.Parse()
+
+	users, err := parseIDs(*usersFlag)
+	if err != nil || len(users) == 0 || *sessionID == 0 {
+		log.Fatalf("нужно указать -session и -users")
+	}
+
+	config.LoadEnv()
+	db.InitDB()
+	defer db.CloseDB()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		winners []*models.Booking
+		taken   int
+		failed  []error
+		start   = make(chan struct{})
+	)
+
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			<-start
+
+			booking, err := services.HoldSeat(dt.HoldSeatDTI{
+				UserID:    userID,
+				SessionID: uint(*sessionID),
+				RowNum:    uint(*row),
+				SeatNum:   uint(*seat),
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				winners = append(winners, booking)
+			case errors.Is(err, services.ErrSeatTaken):
+				taken++
+			default:
+				failed = append(failed, err)
+			}
+		}(users[i%len(users)])
+	}
+
+	close(start)
+	wg.Wait()
+
+	log.Printf("попыток: %d, заняли место: %d, получили «место занято»: %d, прочие ошибки: %d",
+		*workers, len(winners), taken, len(failed))
+	for _, err := range failed {
+		log.Printf("ошибка: %v", err)
+	}
+
+	// Освобождаем место после проверки
+	for _, b := range winners {
+		if err := services.CancelBooking(b.ID, b.CustomerID); err != nil {
+			log.Printf("не удалось снять бронь #%d: %v", b.ID, err)
+		}
+	}
+
+	if len(winners) != 1 || len(failed) > 0 {
+		log.Printf("ПРОВАЛ: ожидался ровно один победитель без прочих ошибок")
+		os.Exit(1)
+	}
+	log.Printf("OK")
+}
+
+func parseIDs(raw string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
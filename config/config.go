@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -39,7 +41,8 @@ func GetDBConnString() string {
 		" password=" + os.Getenv("DB_PASSWORD") +
 		" dbname=" + os.Getenv("DB_NAME") +
 		" port=" + os.Getenv("DB_PORT") +
-		" sslmode=disable"
+		" sslmode=disable" +
+		" TimeZone=UTC" // время в базе и в ответах драйвера — в UTC
 }
 
 // передаем secret-key
@@ -50,3 +53,76 @@ func GetJWTSecret() string {
 	}
 	return secret
 }
+
+// время, на которое место удерживается до оплаты
+func GetBookingHoldTTL() time.Duration {
+	return time.Duration(getEnvInt("BOOKING_HOLD_MINUTES", 10)) * time.Minute
+}
+
+// как часто снимать просроченные брони
+func GetHoldSweepInterval() time.Duration {
+	return time.Duration(getEnvInt("HOLD_SWEEP_INTERVAL_SECONDS", 60)) * time.Second
+}
+
+// как часто отправлять накопившиеся уведомления
+func GetNotifyInterval() time.Duration {
+	return time.Duration(getEnvInt("NOTIFY_INTERVAL_SECONDS", 30)) * time.Second
+}
+
+// перерыв между сеансами в зале (уборка, реклама), если в зале не задан свой
+func GetSessionBuffer() time.Duration {
+	return time.Duration(getEnvInt("SESSION_BUFFER_MINUTES", 15)) * time.Minute
+}
+
+// платёжный провайдер: fake — в памяти процесса, http — внешний шлюз (или локальная заглушка cmd/paystub)
+func GetPaymentProvider() string {
+	return getEnv("PAYMENT_PROVIDER", "fake")
+}
+
+// адрес платёжного шлюза для провайдера http
+func GetPaymentGatewayURL() string {
+	return getEnv("PAYMENT_GATEWAY_URL", "http://localhost:8090")
+}
+
+// куда шлюз отправляет уведомления о смене статуса платежа
+func GetPaymentWebhookURL() string {
+	return getEnv("PAYMENT_WEBHOOK_URL", "http://localhost:8080/payments/webhook")
+}
+
+// секрет для подписи уведомлений шлюза; пока не задан, уведомления не принимаются
+func GetPaymentWebhookSecret() string {
+	return os.Getenv("PAYMENT_WEBHOOK_SECRET")
+}
+
+// как часто сверять со шлюзом платежи, по которым не пришло уведомление
+func GetPaymentReconcileInterval() time.Duration {
+	return time.Duration(getEnvInt("PAYMENT_RECONCILE_INTERVAL_SECONDS", 60)) * time.Second
+}
+
+// как часто сверять журнал проводок с балансами в профилях
+func GetLedgerCheckInterval() time.Duration {
+	return time.Duration(getEnvInt("LEDGER_CHECK_INTERVAL_SECONDS", 3600)) * time.Second
+}
+
+// читаем строку из окружения, при отсутствии берём значение по умолчанию
+func getEnv(key, def string) string {
+	if raw := os.Getenv(key); raw != "" {
+		return raw
+	}
+	return def
+}
+
+// читаем целое число из окружения, при отсутствии или ошибке берём значение по умолчанию
+func getEnvInt(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		log.Printf("Некорректное значение %s=%q, используется %d", key, raw, def)
+		return def
+	}
+	return value
+}
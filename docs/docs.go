@@ -15,39 +15,69 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/films": {
-            "post": {
+        "/admin/bookings": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin-films"
+                    "admin-bookings"
                 ],
-                "summary": "Создать фильм",
+                "summary": "Найти бронирования",
                 "parameters": [
                     {
-                        "description": "Фильм",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateFilmDTI"
-                        }
+                        "type": "integer",
+                        "description": "ID сеанса",
+                        "name": "session_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "reserved / paid / canceled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата сеанса с (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата сеанса по (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы (с 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateFilmDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.AdminBookingListDTO"
                         }
                     },
                     "400": {
@@ -77,122 +107,109 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/films/{id}": {
-            "delete": {
+        "/admin/bookings/{id}/cancel": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin-films"
+                    "admin-bookings"
                 ],
-                "summary": "Удалить фильм",
+                "summary": "Принудительно отменить бронирование клиента",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID фильма",
+                        "description": "ID бронирования",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Причина отмены и необходимость возврата",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.AdminCancelBookingDTI"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/admin/cancellation-policies": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin-films"
-                ],
-                "summary": "Обновить фильм",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID фильма",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Поля для обновления",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
-                    }
+                    "admin-cancellation-policies"
                 ],
+                "summary": "Получить политики отмены",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.CancellationPolicyDTO"
+                            }
                         }
                     },
                     "401": {
@@ -214,9 +231,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/films/{id}/genres": {
+            },
             "post": {
                 "security": [
                     {
@@ -230,32 +245,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-genres"
+                    "admin-cancellation-policies"
                 ],
-                "summary": "Привязать жанр к фильму",
+                "summary": "Создать политику отмены",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID фильма",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "ID жанра",
+                        "description": "Политика и её правила",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.AssignGenreDTI"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateCancellationPolicyDTI"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateCancellationPolicyDTO"
                         }
                     },
                     "400": {
@@ -276,8 +284,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
@@ -291,7 +299,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/films/{id}/genres/{genre_id}": {
+        "/admin/cancellation-policies/{id}": {
             "delete": {
                 "security": [
                     {
@@ -302,23 +310,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-genres"
+                    "admin-cancellation-policies"
                 ],
-                "summary": "Убрать жанр у фильма",
+                "summary": "Удалить политику отмены",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID фильма",
+                        "description": "ID политики",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID жанра",
-                        "name": "genre_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -361,7 +362,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/genres": {
+        "/admin/cinemas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-cinemas"
+                ],
+                "summary": "Получить все кинотеатры",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.CinemaDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
@@ -375,17 +419,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-genres"
+                    "admin-cinemas"
                 ],
-                "summary": "Создать жанр",
+                "summary": "Создать кинотеатр",
                 "parameters": [
                     {
-                        "description": "Жанр",
+                        "description": "Кинотеатр",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateGenreDTI"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateCinemaDTI"
                         }
                     }
                 ],
@@ -393,7 +437,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateGenreDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateCinemaDTO"
                         }
                     },
                     "400": {
@@ -423,7 +467,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/genres/{id}": {
+        "/admin/cinemas/{id}": {
             "delete": {
                 "security": [
                     {
@@ -434,13 +478,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-genres"
+                    "admin-cinemas"
                 ],
-                "summary": "Удалить жанр",
+                "summary": "Удалить кинотеатр (только без действующих залов)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID жанра",
+                        "description": "ID кинотеатра",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -477,6 +521,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -498,19 +548,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-genres"
+                    "admin-cinemas"
                 ],
-                "summary": "Обновить жанр",
+                "summary": "Обновить кинотеатр (частично)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID жанра",
+                        "description": "ID кинотеатра",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Поля для обновления",
+                        "description": "Поля для обновления: name, location, phone, email, timezone, age_check (block или warn)",
                         "name": "input",
                         "in": "body",
                         "required": true,
@@ -559,96 +609,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/posters": {
-            "post": {
+        "/admin/cinemas/{id}/halls": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin-posters"
+                    "admin-halls"
                 ],
-                "summary": "Создать афишу",
+                "summary": "Получить залы кинотеатра",
                 "parameters": [
                     {
-                        "description": "Данные афиши",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreatePosterDTI"
-                        }
+                        "type": "integer",
+                        "description": "ID кинотеатра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreatePosterDTO"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/posters/{id}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "admin-posters"
-                ],
-                "summary": "Удалить афишу",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID афиши",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.HallDTO"
+                            }
                         }
                     },
                     "400": {
@@ -683,7 +674,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -696,32 +687,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-posters"
+                    "admin-halls"
                 ],
-                "summary": "Обновить афишу",
+                "summary": "Создать зал в кинотеатре",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID афиши",
+                        "description": "ID кинотеатра",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Поля для обновления",
+                        "description": "Зал и его схема; capacity должна совпадать с числом мест в схеме",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateHallDTI"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateHallDTO"
                         }
                     },
                     "400": {
@@ -757,8 +748,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/reviews/{id}/approve": {
-            "patch": {
+        "/admin/cinemas/{id}/halls/{hall_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -768,16 +759,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-reviews"
+                    "admin-halls"
                 ],
-                "summary": "Одобрить отзыв",
+                "summary": "Удалить зал (только без предстоящих сеансов)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID отзыва",
+                        "description": "ID кинотеатра",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID зала",
+                        "name": "hall_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -811,6 +809,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -818,29 +822,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/reviews/{id}/reject": {
+            },
             "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "При наличии предстоящих сеансов из схемы нельзя убирать места и закрывать места с бронями",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin-reviews"
+                    "admin-halls"
                 ],
-                "summary": "Отклонить отзыв",
+                "summary": "Обновить зал (частично)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID отзыва",
+                        "description": "ID кинотеатра",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID зала",
+                        "name": "hall_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Поля для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.UpdateHallDTI"
+                        }
                     }
                 ],
                 "responses": {
@@ -874,6 +896,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -883,7 +911,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/sessions": {
+        "/admin/films": {
             "post": {
                 "security": [
                     {
@@ -897,17 +925,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-sessions"
+                    "admin-films"
                 ],
-                "summary": "Создать новый сеанс",
+                "summary": "Создать фильм",
                 "parameters": [
                     {
-                        "description": "Данные для создания сеанса",
+                        "description": "Фильм",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateSessionDTI"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateFilmDTI"
                         }
                     }
                 ],
@@ -915,7 +943,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateSessionDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateFilmDTO"
                         }
                     },
                     "400": {
@@ -945,7 +973,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/sessions/{id}": {
+        "/admin/films/{id}": {
             "delete": {
                 "security": [
                     {
@@ -956,13 +984,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-sessions"
+                    "admin-films"
                 ],
-                "summary": "Удалить сеанс",
+                "summary": "Удалить фильм",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID сеанса",
+                        "description": "ID фильма",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -972,37 +1000,46 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
@@ -1020,13 +1057,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin-sessions"
+                    "admin-films"
                 ],
-                "summary": "Обновить сеанс (частично)",
+                "summary": "Обновить фильм",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID сеанса",
+                        "description": "ID фильма",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1066,12 +1103,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1081,25 +1112,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/bonus/balance": {
-            "get": {
+        "/admin/films/{id}/genres": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bonus"
+                    "admin-genres"
+                ],
+                "summary": "Привязать жанр к фильму",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID жанра",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.AssignGenreDTI"
+                        }
+                    }
                 ],
-                "summary": "Получить текущий баланс бонусов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.BonusBalanceDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1108,6 +1166,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1117,8 +1187,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/bonus/history": {
-            "get": {
+        "/admin/films/{id}/genres/{genre_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1128,17 +1198,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bonus"
+                    "admin-genres"
+                ],
+                "summary": "Убрать жанр у фильма",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "genre_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить историю бонусов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.BonusHistoryDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1147,6 +1236,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1156,49 +1257,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/bookings": {
-            "post": {
+        "/admin/format-surcharges": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bookings"
-                ],
-                "summary": "Забронировать билет",
-                "parameters": [
-                    {
-                        "description": "Данные для бронирования",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateBookingDTI"
-                        }
-                    }
+                    "admin-sessions"
                 ],
+                "summary": "Получить наценки по форматам показа",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateBookingDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.FormatSurchargeDTO"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
@@ -1212,27 +1302,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/bookings/{id}": {
-            "delete": {
+        "/admin/format-surcharges/{format}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Наценка подставляется в новые сеансы формата и добавляется к цене любого места. Уже созданные сеансы не меняются",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bookings"
+                    "admin-sessions"
                 ],
-                "summary": "Отменить бронирование",
+                "summary": "Задать наценку формата показа",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID бронирования",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Формат: 2d, 3d, imax, imax_3d",
+                        "name": "format",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Наценка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.FormatSurchargeDTI"
+                        }
                     }
                 ],
                 "responses": {
@@ -1260,12 +1363,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1275,31 +1372,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/films": {
-            "get": {
+        "/admin/genres": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "admin-genres"
                 ],
-                "summary": "Получить список фильмов",
+                "summary": "Создать жанр",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Список ID жанров через запятую",
-                        "name": "genres",
-                        "in": "query"
+                        "description": "Жанр",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateGenreDTI"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.FilmDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateGenreDTO"
                         }
                     },
                     "400": {
@@ -1308,6 +1413,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1317,19 +1434,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/films/{id}": {
-            "get": {
+        "/admin/genres/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "admin-genres"
                 ],
-                "summary": "Получить фильм по ID",
+                "summary": "Удалить жанр",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID фильма",
+                        "description": "ID жанра",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1339,7 +1461,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.FilmDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
                         }
                     },
                     "400": {
@@ -1348,6 +1470,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1361,34 +1495,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/films/{id}/genres": {
-            "get": {
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "admin-genres"
                 ],
-                "summary": "Получить жанры фильма",
+                "summary": "Обновить жанр",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID фильма",
+                        "description": "ID жанра",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Поля для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.GenreDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
                         }
                     },
                     "400": {
@@ -1397,42 +1543,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/films/{id}/rating": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "reviews"
-                ],
-                "summary": "Получить рейтинг фильма",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID фильма",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.FilmRatingDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
@@ -1446,36 +1570,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/films/{id}/reviews": {
+        "/admin/hall-types": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
-                ],
-                "summary": "Получить отзывы по фильму",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID фильма",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin-hall-types"
                 ],
+                "summary": "Получить все типы залов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.ReviewDTO"
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.HallTypeDTO"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
@@ -1501,24 +1627,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
+                    "admin-hall-types"
                 ],
-                "summary": "Добавить отзыв (на модерацию)",
+                "summary": "Создать тип зала",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID фильма",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Отзыв",
+                        "description": "Тип зала",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateReviewDTI"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateHallTypeDTI"
                         }
                     }
                 ],
@@ -1526,7 +1645,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateReviewDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateHallTypeDTO"
                         }
                     },
                     "400": {
@@ -1541,32 +1660,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/genres": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "genres"
-                ],
-                "summary": "Получить все жанры",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.GenreDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1578,34 +1681,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "consumes": [
-                    "application/json"
+        "/admin/hall-types/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin-hall-types"
                 ],
-                "summary": "Логин пользователя",
+                "summary": "Удалить тип зала (только неиспользуемый)",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.LoginDTI"
-                        }
+                        "type": "integer",
+                        "description": "ID типа зала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.LoginDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1613,27 +1722,23 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/posters": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "posters"
-                ],
-                "summary": "Получить список афиш",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.PosterDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1643,27 +1748,52 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/profile": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "admin-hall-types"
+                ],
+                "summary": "Обновить тип зала (частично)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID типа зала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Поля для обновления: name, desc",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
                 ],
-                "summary": "Получить данные профиля",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ProfileDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1672,6 +1802,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1679,8 +1821,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/admin/halls/{id}/structure": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1693,17 +1837,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "admin-halls"
                 ],
-                "summary": "Обновить данные профиля (частично)",
+                "summary": "Заменить схему зала (ряды, типы мест, координаты, закрытые места)",
                 "parameters": [
                     {
-                        "description": "Поля для обновления",
+                        "type": "integer",
+                        "description": "ID зала",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Схема зала",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/CinemaBooking_pkg_services.HallStructure"
                         }
                     }
                 ],
@@ -1711,7 +1862,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.UpdateProfileDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_services.HallStructure"
                         }
                     },
                     "400": {
@@ -1726,6 +1877,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1735,49 +1904,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile/password": {
-            "patch": {
+        "/admin/ledger/check": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Ищет несбалансированные проводки и расхождения балансов в профилях с суммой движений по счетам пользователей",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
-                ],
-                "summary": "Изменить пароль",
-                "parameters": [
-                    {
-                        "description": "Смена пароля",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ChangePasswordDTI"
-                        }
-                    }
+                    "admin-ledger"
                 ],
+                "summary": "Сверить журнал проводок",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ChangePasswordDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.LedgerCheckDTO"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
@@ -1791,8 +1947,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
+        "/admin/posters": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -1800,17 +1961,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin-posters"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Создать афишу",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
+                        "description": "Данные афиши",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RegisterDTI"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreatePosterDTI"
                         }
                     }
                 ],
@@ -1818,7 +1979,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RegisterDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreatePosterDTO"
                         }
                     },
                     "400": {
@@ -1826,11 +1987,29 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/reviews/{id}": {
+        "/admin/posters/{id}": {
             "delete": {
                 "security": [
                     {
@@ -1841,13 +2020,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
+                    "admin-posters"
                 ],
-                "summary": "Удалить свой отзыв",
+                "summary": "Удалить афишу",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID отзыва",
+                        "description": "ID афиши",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1891,25 +2070,70 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions": {
-            "get": {
-                "produces": [
-                    "application/json"
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin-posters"
+                ],
+                "summary": "Обновить афишу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID афиши",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Поля для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
                 ],
-                "summary": "Получить все предстоящие сеансы",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1921,19 +2145,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/film/{id}": {
-            "get": {
+        "/admin/reviews/{id}/approve": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin-reviews"
                 ],
-                "summary": "Получить предстоящие сеансы по фильму",
+                "summary": "Одобрить отзыв",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID фильма",
+                        "description": "ID отзыва",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1943,10 +2172,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
                         }
                     },
                     "400": {
@@ -1955,6 +2181,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1964,19 +2208,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{id}/seats": {
-            "get": {
+        "/admin/reviews/{id}/reject": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin-reviews"
                 ],
-                "summary": "Получить все места на сеанс с их статусом (free/taken)",
+                "summary": "Отклонить отзыв",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID сеанса",
+                        "description": "ID отзыва",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1986,10 +2235,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SeatDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
                         }
                     },
                     "400": {
@@ -1998,6 +2244,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2013,7 +2271,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallet/balance": {
+        "/admin/session-series": {
             "get": {
                 "security": [
                     {
@@ -2024,14 +2282,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin-session-series"
                 ],
-                "summary": "Получить текущий баланс",
+                "summary": "Получить все серии сеансов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.PaymentDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionSeriesDTO"
+                            }
                         }
                     },
                     "401": {
@@ -2040,6 +2301,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2047,30 +2314,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/wallet/payments": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Все сеансы создаются одной транзакцией; при любом пересечении не создаётся ничего",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin-session-series"
+                ],
+                "summary": "Создать серию сеансов по шаблону",
+                "parameters": [
+                    {
+                        "description": "Шаблон расписания",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateSessionSeriesDTI"
+                        }
+                    }
                 ],
-                "summary": "Получить историю пополнений",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CinemaBooking_pkg_dt.PaymentHistoryDTO"
-                            }
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateSessionSeriesDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
                     "401": {
@@ -2079,6 +2362,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleConflictResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2088,13 +2389,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallet/refill": {
+        "/admin/session-series/preview": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Показывает, какие сеансы будут созданы и с какими существующими сеансами зала они пересекаются. Ничего не сохраняет",
                 "consumes": [
                     "application/json"
                 ],
@@ -2102,17 +2404,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin-session-series"
                 ],
-                "summary": "Пополнить баланс",
+                "summary": "Предпросмотр серии сеансов по шаблону",
                 "parameters": [
                     {
-                        "description": "Сумма пополнения",
+                        "description": "Шаблон расписания",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RefillBalanceDTI"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateSessionSeriesDTI"
                         }
                     }
                 ],
@@ -2120,7 +2422,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionSeriesPreviewDTO"
                         }
                     },
                     "400": {
@@ -2135,6 +2437,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2143,77 +2457,4285 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "CinemaBooking_pkg_dt.AssignGenreDTI": {
-            "type": "object",
-            "required": [
-                "genre_id"
-            ],
-            "properties": {
-                "genre_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "CinemaBooking_pkg_dt.BonusBalanceDTO": {
-            "type": "object",
-            "properties": {
-                "balance": {
-                    "type": "number"
-                }
-            }
         },
-        "CinemaBooking_pkg_dt.BonusHistoryDTO": {
-            "type": "object",
-            "properties": {
-                "history_balance": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/CinemaBooking_pkg_models.BonusHistory"
+        "/admin/session-series/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "CinemaBooking_pkg_dt.ChangePasswordDTI": {
-            "type": "object",
-            "required": [
-                "new_password",
-                "old_password",
-                "repeat_new"
-            ],
-            "properties": {
-                "new_password": {
-                    "type": "string"
-                },
-                "old_password": {
-                    "type": "string"
-                },
-                "repeat_new": {
-                    "type": "string"
-                }
-            }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-session-series"
+                ],
+                "summary": "Получить серию с её сеансами",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID серии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionSeriesDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сеансы с действующими бронями не удаляются и возвращаются в kept_ids",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-session-series"
+                ],
+                "summary": "Удалить ещё не начавшиеся сеансы серии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID серии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.SeriesDeleteResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-session-series"
+                ],
+                "summary": "Изменить ещё не начавшиеся сеансы серии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID серии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Фильм, базовая цена, цены категорий",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.UpdateSessionSeriesDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.SeriesUpdateResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sessions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-sessions"
+                ],
+                "summary": "Создать новый сеанс",
+                "parameters": [
+                    {
+                        "description": "Данные для создания сеанса",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateSessionDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateSessionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sessions/validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Учитывает длительность фильма и перерыв зала. Для переноса существующего сеанса передайте session_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-sessions"
+                ],
+                "summary": "Проверить сеанс на пересечения в зале без сохранения",
+                "parameters": [
+                    {
+                        "description": "Фильм, зал и время начала",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ValidateSessionDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleCheckDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сеанс не удаляется, а отменяется: оплаченные места возвращаются полностью, клиенты получают уведомление",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-sessions"
+                ],
+                "summary": "Снять сеанс с расписания",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сеанса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionCancelSummaryDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Поля: film_id, hall_id, start_time, price, format, audio_language, subtitle_language, late_show, surcharge. При смене формата без surcharge подставляется наценка нового формата",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-sessions"
+                ],
+                "summary": "Обновить сеанс (частично)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сеанса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Поля для обновления: film_id, hall_id, start_time, price",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sessions/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оплаченные места возвращаются полностью (деньги и бонусы), удержания снимаются, каждому клиенту ставится уведомление. Возвращает сводку возвратов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-sessions"
+                ],
+                "summary": "Отменить сеанс с возвратом всех билетов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сеанса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отмены",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CancelSessionDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionCancelSummaryDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sessions/{id}/prices": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Для каждой категории указывается либо price, либо multiplier к базовой цене. Категории без правила продаются по базовой цене\nmultiplier — от 0 (не включая) до 100 с точностью до 0.0001, например 1.5",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-sessions"
+                ],
+                "summary": "Задать цены категорий мест для сеанса",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сеанса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Цены по категориям",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionPriceDTI"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bonus/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonus"
+                ],
+                "summary": "Получить текущий баланс бонусов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.BonusBalanceDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bonus/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonus"
+                ],
+                "summary": "Получить историю бонусов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.BonusHistoryDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Получить историю своих бронирований",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "upcoming / past / cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы (с 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.BookingListDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "payment_method=wallet (по умолчанию) — оплата с баланса сразу (201).\npayment_method=card — место удерживается, создаётся платёж в шлюзе (202); бронь оплачивается после его подтверждения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Забронировать билет",
+                "parameters": [
+                    {
+                        "description": "Данные для бронирования",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateBookingDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateBookingDTO"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateBookingDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/hold": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Временно удержать место до оплаты",
+                "parameters": [
+                    {
+                        "description": "Сеанс и место",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.HoldSeatDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.HoldSeatDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Получить билет (подробности бронирования)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бронирования",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.BookingDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Отменить своё бронирование",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бронирования",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Оплатить удержанное место",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бронирования",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Параметры оплаты",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ConfirmBookingDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateBookingDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/payment": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Получить оплату брони картой и её статус",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бронирования",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.BookingPaymentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/payment/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Передаёт подтверждение в шлюз. Если шлюз ответит позже, место остаётся удержанным до уведомления.\nПри отказе бронь снимается и место освобождается",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Подтвердить оплату брони картой (оплатить)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бронирования",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.BookingPaymentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/refund-preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Узнать сумму возврата при отмене бронирования",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бронирования",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RefundPreviewDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Получить список кинотеатров",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.CinemaDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Получить кинотеатр с его залами",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID кинотеатра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CinemaDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cinemas/{id}/sessions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cinemas"
+                ],
+                "summary": "Расписание кинотеатра на день, сгруппированное по фильмам и залам",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID кинотеатра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата YYYY-MM-DD, по умолчанию сегодня",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CinemaScheduleDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/films": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Получить список фильмов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Список ID жанров через запятую",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только фильмы, доступные текущему пользователю по возрасту (нужен токен)",
+                        "name": "allowed_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.FilmDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/films/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Получить фильм по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.FilmDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/films/{id}/genres": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получить жанры фильма",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.GenreDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/films/{id}/rating": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Получить рейтинг фильма",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.FilmRatingDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/films/{id}/reviews": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Получить отзывы по фильму",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.ReviewDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Добавить отзыв (на модерацию)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отзыв",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateReviewDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateReviewDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получить все жанры",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.GenreDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Логин пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.LoginDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.LoginDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Например, об отмене сеанса и возврате средств. Новые сверху",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Получить свои уведомления",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.NotificationDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Купить несколько мест одним заказом",
+                "parameters": [
+                    {
+                        "description": "Сеанс и список мест",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.CreateOrderDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.OrderDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Получить заказ",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.OrderDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Отменить заказ целиком",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/refund-preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Узнать сумму возврата при отмене заказа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RefundPreviewDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Тело подписывается HMAC-SHA256 общим секретом, подпись в заголовке X-Payment-Signature (t=\u003cunix\u003e,v1=\u003chex\u003e).\nСтатус платежа запрашивается у шлюза, из события берутся только ID события и платежа.\nПовтор события с тем же ID возвращает duplicate и ничего не меняет; завершённый платёж запоздавшими событиями не меняется",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Уведомление платёжного шлюза о смене статуса платежа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Подпись уведомления",
+                        "name": "X-Payment-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Событие платежа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_payments.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posters": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posters"
+                ],
+                "summary": "Получить список афиш",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.PosterDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Получить данные профиля",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ProfileDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Обновить данные профиля (частично)",
+                "parameters": [
+                    {
+                        "description": "Поля для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.UpdateProfileDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/password": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Изменить пароль",
+                "parameters": [
+                    {
+                        "description": "Смена пароля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ChangePasswordDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ChangePasswordDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RegisterDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RegisterDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Удалить свой отзыв",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ServAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "description": "Фильтры по параметрам показа: формат, язык звука, субтитры (код языка или none), поздний сеанс 18+",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Получить все предстоящие сеансы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Формат: 2d, 3d, imax, imax_3d",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык звука, например ru или en",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык субтитров или none — без субтитров",
+                        "name": "subtitles",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только поздние сеансы 18+ (true) или только обычные (false)",
+                        "name": "late_show",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только сеансы, доступные текущему пользователю по возрасту (нужен токен)",
+                        "name": "allowed_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/film/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Получить предстоящие сеансы по фильму",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: 2d, 3d, imax, imax_3d",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык звука, например ru или en",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык субтитров или none — без субтитров",
+                        "name": "subtitles",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только поздние сеансы 18+ (true) или только обычные (false)",
+                        "name": "late_show",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только сеансы, доступные текущему пользователю по возрасту (нужен токен)",
+                        "name": "allowed_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/search": {
+            "get": {
+                "description": "Даты YYYY-MM-DD включительно (по умолчанию от сегодня на 2 месяца), окно времени HH:MM может переходить через полночь. Жанры — ID через запятую, у фильма должны быть все. Цена — обычного места с наценкой за формат",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Поиск сеансов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Дата с (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата по (YYYY-MM-DD), включительно",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало не раньше (HH:MM)",
+                        "name": "time_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало раньше (HH:MM)",
+                        "name": "time_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID кинотеатра",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID типа зала",
+                        "name": "hall_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID фильма",
+                        "name": "film_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Список ID жанров через запятую",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальный возрастной рейтинг",
+                        "name": "max_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Цена от",
+                        "name": "price_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Цена до",
+                        "name": "price_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Нужно свободных мест подряд",
+                        "name": "adjacent_seats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: 2d, 3d, imax, imax_3d",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык звука",
+                        "name": "audio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык субтитров или none",
+                        "name": "subtitles",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Поздний сеанс 18+",
+                        "name": "late_show",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только сеансы, доступные текущему пользователю по возрасту (нужен токен)",
+                        "name": "allowed_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start_time, price или title",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc или desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (до 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionSearchResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}/prices": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Получить цены сеанса по категориям мест",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сеанса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionPriceDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}/seats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Получить карту мест сеанса: тип, подпись, координаты, цена и статус (free/taken/blocked)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сеанса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.SeatDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Получить текущий баланс",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.PaymentDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Получить историю операций по балансу",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.PaymentHistoryDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/refill": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт платёж в шлюзе в статусе pending. Баланс пополняется только после подтверждения шлюзом",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Начать пополнение баланса",
+                "parameters": [
+                    {
+                        "description": "Сумма пополнения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RefillBalanceDTI"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RefillDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/refills": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Получить свои пополнения через платёжный шлюз",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CinemaBooking_pkg_dt.RefillDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/refills/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Получить пополнение и его статус",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пополнения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RefillDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/refills/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Передаёт подтверждение в шлюз. Если шлюз ответит позже, пополнение останется в статусе pending до уведомления",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Подтвердить пополнение (оплатить)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пополнения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.RefillDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/CinemaBooking_pkg_dt.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "CinemaBooking_pkg_dt.AdminBookingDTO": {
+            "type": "object",
+            "properties": {
+                "cancel_reason": {
+                    "type": "string"
+                },
+                "canceled_at": {
+                    "type": "string"
+                },
+                "canceled_by": {
+                    "type": "integer"
+                },
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "film_id": {
+                    "type": "integer"
+                },
+                "film_title": {
+                    "type": "string"
+                },
+                "hall_id": {
+                    "type": "integer"
+                },
+                "hall_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "local_start_time": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.PaymentMethod"
+                },
+                "received_bonus": {
+                    "type": "integer"
+                },
+                "refunded_amount": {
+                    "type": "integer"
+                },
+                "row_num": {
+                    "type": "integer"
+                },
+                "seat_num": {
+                    "type": "integer"
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "spend_bonus": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.BookingStatus"
+                },
+                "total_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.AdminBookingListDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.AdminBookingDTO"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.AdminCancelBookingDTI": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "refund": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.AssignGenreDTI": {
+            "type": "object",
+            "required": [
+                "genre_id"
+            ],
+            "properties": {
+                "genre_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.BonusBalanceDTO": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.BonusHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "history_balance": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.BonusHistoryItemDTO"
+                    }
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.BonusHistoryItemDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.BookingDetailDTO": {
+            "type": "object",
+            "properties": {
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "film_id": {
+                    "type": "integer"
+                },
+                "film_title": {
+                    "type": "string"
+                },
+                "hall_id": {
+                    "type": "integer"
+                },
+                "hall_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "local_start_time": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.PaymentMethod"
+                },
+                "received_bonus": {
+                    "type": "integer"
+                },
+                "refunded_amount": {
+                    "type": "integer"
+                },
+                "row_num": {
+                    "type": "integer"
+                },
+                "seat_num": {
+                    "type": "integer"
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "spend_bonus": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.BookingStatus"
+                },
+                "total_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.BookingListDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.BookingDetailDTO"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.BookingPaymentDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "booking_id": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_money.Currency"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CancelSessionDTI": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CancellationPolicyDTO": {
+            "type": "object",
+            "properties": {
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "hall_type_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.CancellationRuleDTO"
+                    }
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CancellationRuleDTI": {
+            "type": "object",
+            "properties": {
+                "min_hours_before": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "refund_percent": {
+                    "type": "integer",
+                    "maximum": 100
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CancellationRuleDTO": {
+            "type": "object",
+            "properties": {
+                "min_hours_before": {
+                    "type": "number"
+                },
+                "refund_percent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.ChangePasswordDTI": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password",
+                "repeat_new"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                },
+                "repeat_new": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.ChangePasswordDTO": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CinemaDTO": {
+            "type": "object",
+            "properties": {
+                "age_check": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "halls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.CinemaHallDTO"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CinemaHallDTO": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "hall_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CinemaScheduleDTO": {
+            "type": "object",
+            "properties": {
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "cinema_name": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "films": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleFilmDTO"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.ConfirmBookingDTI": {
+            "type": "object",
+            "properties": {
+                "use_bonus": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateBookingDTI": {
+            "type": "object",
+            "required": [
+                "row_num",
+                "seat_num",
+                "session_id",
+                "use_bonus",
+                "user_id"
+            ],
+            "properties": {
+                "payment_method": {
+                    "description": "wallet (по умолчанию) — списать с баланса сразу, card — удержать место и оплатить картой через шлюз",
+                    "enum": [
+                        "wallet",
+                        "card"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/CinemaBooking_pkg_models.PaymentMethod"
+                        }
+                    ]
+                },
+                "row_num": {
+                    "type": "integer"
+                },
+                "seat_num": {
+                    "type": "integer"
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "use_bonus": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateBookingDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "до какого момента место ждёт оплаты картой",
+                    "type": "string"
+                },
+                "payment": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_dt.BookingPaymentDTO"
+                },
+                "status": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.BookingStatus"
+                },
+                "status_id": {
+                    "type": "integer"
+                },
+                "warning": {
+                    "description": "возрастное предупреждение кинотеатра",
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateCancellationPolicyDTI": {
+            "type": "object",
+            "required": [
+                "name",
+                "rules"
+            ],
+            "properties": {
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "hall_type_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.CancellationRuleDTI"
+                    }
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateCancellationPolicyDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateCinemaDTI": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "age_check": {
+                    "description": "при возрасте ниже ограничения: block — не продавать (по умолчанию), warn — продать с предупреждением",
+                    "type": "string",
+                    "enum": [
+                        "block",
+                        "warn"
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 11
+                },
+                "timezone": {
+                    "description": "часовой пояс IANA, по умолчанию Europe/Moscow",
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateCinemaDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateFilmDTI": {
+            "type": "object",
+            "required": [
+                "age_rating",
+                "description",
+                "duration",
+                "release_date",
+                "title"
+            ],
+            "properties": {
+                "age_rating": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateFilmDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateGenreDTI": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateGenreDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateHallDTI": {
+            "type": "object",
+            "required": [
+                "capacity",
+                "hall_type_id",
+                "name",
+                "structure"
+            ],
+            "properties": {
+                "buffer_minutes": {
+                    "description": "перерыв между сеансами в минутах; не задан — значение по умолчанию",
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "hall_type_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "structure": {
+                    "type": "object"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateHallDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateHallTypeDTI": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateHallTypeDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateOrderDTI": {
+            "type": "object",
+            "required": [
+                "seats",
+                "session_id"
+            ],
+            "properties": {
+                "seats": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.SeatDTI"
+                    }
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "use_bonus": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreatePosterDTI": {
+            "type": "object",
+            "required": [
+                "film_id",
+                "image_url"
+            ],
+            "properties": {
+                "film_id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreatePosterDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateReviewDTI": {
+            "type": "object",
+            "required": [
+                "comment",
+                "rating"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateReviewDTO": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateSessionDTI": {
+            "type": "object",
+            "required": [
+                "film_id",
+                "hall_id",
+                "price",
+                "start"
+            ],
+            "properties": {
+                "audio_language": {
+                    "type": "string"
+                },
+                "film_id": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "hall_id": {
+                    "type": "integer"
+                },
+                "late_show": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "description": "базовая цена (обычное место)",
+                    "type": "integer"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionPriceDTI"
+                    }
+                },
+                "start": {
+                    "description": "RFC3339 или местное время кинотеатра YYYY-MM-DDTHH:MM",
+                    "type": "string"
+                },
+                "subtitle_language": {
+                    "type": "string"
+                },
+                "surcharge": {
+                    "description": "если не указана — берётся наценка формата",
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateSessionDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateSessionSeriesDTI": {
+            "type": "object",
+            "required": [
+                "date_from",
+                "date_to",
+                "film_id",
+                "hall_id",
+                "price",
+                "slots",
+                "weekdays"
+            ],
+            "properties": {
+                "audio_language": {
+                    "type": "string"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "film_id": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "hall_id": {
+                    "type": "integer"
+                },
+                "late_show": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionPriceDTI"
+                    }
+                },
+                "slots": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subtitle_language": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CreateSessionSeriesDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "session_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.CustomerRefundDTO": {
+            "type": "object",
+            "properties": {
+                "bonus_clawback": {
+                    "type": "integer"
+                },
+                "bonus_returned": {
+                    "type": "integer"
+                },
+                "money": {
+                    "type": "integer"
+                },
+                "seats": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.FilmDTO": {
+            "type": "object",
+            "properties": {
+                "age_rating": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.GenreDTO"
+                    }
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.FilmRatingDTO": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.FormatSurchargeDTI": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.FormatSurchargeDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.GenreDTO": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
         },
-        "CinemaBooking_pkg_dt.ChangePasswordDTO": {
+        "CinemaBooking_pkg_dt.HallDTO": {
             "type": "object",
             "properties": {
-                "status": {
+                "buffer_minutes": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "hall_type": {
                     "type": "string"
                 },
-                "user_id": {
+                "hall_type_id": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "structure": {
+                    "type": "object"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateBookingDTI": {
+        "CinemaBooking_pkg_dt.HallTypeDTO": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.HoldSeatDTI": {
             "type": "object",
             "required": [
                 "row_num",
                 "seat_num",
-                "session_id",
-                "use_bonus",
-                "user_id"
+                "session_id"
             ],
             "properties": {
                 "row_num": {
@@ -2224,102 +6746,342 @@ const docTemplate = `{
                 },
                 "session_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.HoldSeatDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
                 },
-                "use_bonus": {
+                "status": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.BookingStatus"
+                },
+                "warning": {
+                    "description": "возрастное предупреждение кинотеатра",
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.LedgerCheckDTO": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "consistent": {
                     "type": "boolean"
                 },
-                "user_id": {
-                    "type": "integer"
+                "drift": {
+                    "description": "расхождения кэша в профиле с журналом",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.LedgerDriftDTO"
+                    }
+                },
+                "system_balances": {
+                    "description": "остатки общих счетов: выручка, возвраты, шлюз и т.д.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "unbalanced_entries": {
+                    "description": "проводки, движения которых не сходятся в ноль",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateBookingDTO": {
+        "CinemaBooking_pkg_dt.LedgerDriftDTO": {
             "type": "object",
             "properties": {
-                "status": {
-                    "$ref": "#/definitions/CinemaBooking_pkg_models.BookingStatus"
+                "account": {
+                    "description": "wallet или bonus",
+                    "type": "string"
                 },
-                "status_id": {
+                "cached": {
+                    "description": "баланс в профиле",
+                    "type": "integer"
+                },
+                "ledger": {
+                    "description": "сумма движений в журнале",
+                    "type": "integer"
+                },
+                "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateFilmDTI": {
+        "CinemaBooking_pkg_dt.LoginDTI": {
             "type": "object",
             "required": [
-                "age_rating",
-                "description",
-                "duration",
-                "release_date",
-                "title"
+                "email",
+                "password"
             ],
             "properties": {
-                "age_rating": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.LoginDTO": {
+            "type": "object",
+            "properties": {
+                "jwt": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.NotificationDTO": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "description": {
+                "kind": {
                     "type": "string"
                 },
-                "duration": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.OrderBookingDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "integer"
                 },
-                "genres": {
+                "row_num": {
+                    "type": "integer"
+                },
+                "seat_num": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.OrderDTO": {
+            "type": "object",
+            "properties": {
+                "bookings": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.OrderBookingDTO"
                     }
                 },
-                "release_date": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "title": {
+                "received_bonus": {
+                    "type": "integer"
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "spend_bonus": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.BookingStatus"
+                },
+                "total_price": {
+                    "type": "integer"
+                },
+                "warning": {
+                    "description": "возрастное предупреждение кинотеатра",
                     "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateFilmDTO": {
+        "CinemaBooking_pkg_dt.PaymentDTO": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_money.Currency"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.PaymentHistoryDTO": {
             "type": "object",
             "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.PosterDTO": {
+            "type": "object",
+            "properties": {
+                "film_id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateGenreDTI": {
+        "CinemaBooking_pkg_dt.ProfileDTO": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                },
+                "bonus": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "валюта баланса",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/CinemaBooking_pkg_money.Currency"
+                        }
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "second_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.RefillBalanceDTI": {
             "type": "object",
             "required": [
-                "name"
+                "amount"
             ],
             "properties": {
-                "name": {
-                    "type": "string"
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "если не указана — валюта сервиса",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/CinemaBooking_pkg_money.Currency"
+                        }
+                    ]
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateGenreDTO": {
+        "CinemaBooking_pkg_dt.RefillDTO": {
             "type": "object",
             "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_money.Currency"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreatePosterDTI": {
+        "CinemaBooking_pkg_dt.RefundPreviewDTO": {
+            "type": "object",
+            "properties": {
+                "bonus_clawback": {
+                    "type": "integer"
+                },
+                "bonus_return": {
+                    "type": "integer"
+                },
+                "policy_id": {
+                    "type": "integer"
+                },
+                "policy_name": {
+                    "type": "string"
+                },
+                "refund_amount": {
+                    "type": "integer"
+                },
+                "refund_percent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.RegisterDTI": {
             "type": "object",
             "required": [
-                "film_id",
-                "image_url"
+                "email",
+                "first_name",
+                "login",
+                "password",
+                "phone"
             ],
             "properties": {
-                "film_id": {
-                    "type": "integer"
+                "birthday": {
+                    "type": "string"
                 },
-                "image_url": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "second_name": {
                     "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreatePosterDTO": {
+        "CinemaBooking_pkg_dt.RegisterDTO": {
             "type": "object",
             "properties": {
                 "id": {
@@ -2327,299 +7089,516 @@ const docTemplate = `{
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateReviewDTI": {
+        "CinemaBooking_pkg_dt.ReviewDTO": {
             "type": "object",
-            "required": [
-                "comment",
-                "rating"
-            ],
             "properties": {
                 "comment": {
                     "type": "string"
                 },
                 "rating": {
                     "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateReviewDTO": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "CinemaBooking_pkg_dt.CreateSessionDTI": {
+        "CinemaBooking_pkg_dt.ScheduleCheckDTO": {
             "type": "object",
-            "required": [
-                "film_id",
-                "hall_id",
-                "price",
-                "start"
-            ],
             "properties": {
-                "film_id": {
-                    "type": "integer"
+                "conflict_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "hall_id": {
-                    "type": "integer"
+                "end": {
+                    "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "ok": {
+                    "type": "boolean"
                 },
                 "start": {
                     "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.CreateSessionDTO": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "CinemaBooking_pkg_dt.ErrorResponse": {
+        "CinemaBooking_pkg_dt.ScheduleConflictResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "string"
                 },
+                "conflict_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "error": {
                     "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.FilmDTO": {
+        "CinemaBooking_pkg_dt.ScheduleFilmDTO": {
             "type": "object",
             "properties": {
                 "age_rating": {
                     "type": "integer"
                 },
-                "description": {
-                    "type": "string"
-                },
                 "duration": {
                     "type": "integer"
                 },
-                "genres": {
+                "film_id": {
+                    "type": "integer"
+                },
+                "halls": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/CinemaBooking_pkg_dt.GenreDTO"
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleHallDTO"
                     }
                 },
-                "release_date": {
-                    "type": "string"
-                },
                 "title": {
                     "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.FilmRatingDTO": {
+        "CinemaBooking_pkg_dt.ScheduleHallDTO": {
             "type": "object",
             "properties": {
-                "message": {
+                "hall_id": {
+                    "type": "integer"
+                },
+                "hall_name": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "number"
+                "hall_type": {
+                    "type": "string"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.ScheduleSessionDTO"
+                    }
                 }
             }
         },
-        "CinemaBooking_pkg_dt.GenreDTO": {
+        "CinemaBooking_pkg_dt.ScheduleSessionDTO": {
             "type": "object",
             "properties": {
-                "name": {
+                "audio_language": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "late_show": {
+                    "type": "boolean"
+                },
+                "local_start_time": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "subtitle_language": {
                     "type": "string"
+                },
+                "surcharge": {
+                    "type": "integer"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.LoginDTI": {
+        "CinemaBooking_pkg_dt.SeatDTI": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "row_num",
+                "seat_num"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "row_num": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string"
+                "seat_num": {
+                    "type": "integer"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.LoginDTO": {
+        "CinemaBooking_pkg_dt.SeatDTO": {
             "type": "object",
             "properties": {
-                "jwt": {
+                "label": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "row_label": {
+                    "type": "string"
+                },
+                "seat": {
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "free / taken / blocked",
                     "type": "string"
+                },
+                "type": {
+                    "description": "standard / vip / couch / wheelchair",
+                    "type": "string"
+                },
+                "x": {
+                    "type": "number"
+                },
+                "y": {
+                    "type": "number"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.PaymentDTO": {
+        "CinemaBooking_pkg_dt.SeriesDeleteResultDTO": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "type": "number"
+                "deleted": {
+                    "type": "integer"
+                },
+                "kept_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "CinemaBooking_pkg_dt.PaymentHistoryDTO": {
+        "CinemaBooking_pkg_dt.SeriesOccurrenceDTO": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
+                "conflict_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "created_at": {
+                "end": {
                     "type": "string"
                 },
-                "operation": {
+                "start": {
                     "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.PosterDTO": {
+        "CinemaBooking_pkg_dt.SeriesUpdateResultDTO": {
             "type": "object",
             "properties": {
-                "film_id": {
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.ServAnswerDTO": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.SessionCancelSummaryDTO": {
+            "type": "object",
+            "properties": {
+                "bonus_clawback": {
+                    "description": "снято начисленных бонусов",
                     "type": "integer"
                 },
-                "url": {
+                "bonus_returned": {
+                    "description": "возвращено потраченных бонусов",
+                    "type": "integer"
+                },
+                "bookings": {
+                    "description": "отменено мест",
+                    "type": "integer"
+                },
+                "customers": {
+                    "description": "уведомлено клиентов",
+                    "type": "integer"
+                },
+                "orders": {
+                    "description": "отменено заказов",
+                    "type": "integer"
+                },
+                "reason": {
                     "type": "string"
+                },
+                "refunded_money": {
+                    "description": "возвращено денег на балансы",
+                    "type": "integer"
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.CustomerRefundDTO"
+                    }
+                },
+                "session_id": {
+                    "type": "integer"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.ProfileDTO": {
+        "CinemaBooking_pkg_dt.SessionDTO": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "type": "number"
+                "audio_language": {
+                    "type": "string"
                 },
-                "bonus": {
-                    "type": "number"
+                "film_id": {
+                    "type": "integer"
                 },
-                "email": {
+                "format": {
                     "type": "string"
                 },
-                "first_name": {
+                "hall_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "late_show": {
+                    "type": "boolean"
+                },
+                "local_start_time": {
                     "type": "string"
                 },
-                "second_name": {
+                "price": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "subtitle_language": {
+                    "type": "string"
+                },
+                "surcharge": {
+                    "description": "наценка за формат",
+                    "type": "integer"
+                },
+                "timezone": {
                     "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.RefillBalanceDTI": {
+        "CinemaBooking_pkg_dt.SessionPriceDTI": {
             "type": "object",
             "required": [
-                "amount"
+                "seat_type"
             ],
             "properties": {
-                "amount": {
+                "multiplier": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "seat_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_dt.SessionPriceDTO": {
+            "type": "object",
+            "properties": {
+                "multiplier": {
                     "type": "number"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "seat_type": {
+                    "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.RegisterDTI": {
+        "CinemaBooking_pkg_dt.SessionSearchItemDTO": {
             "type": "object",
-            "required": [
-                "email",
-                "first_name",
-                "login",
-                "password",
-                "phone"
-            ],
             "properties": {
-                "birthday": {
+                "age_rating": {
+                    "type": "integer"
+                },
+                "audio_language": {
                     "type": "string"
                 },
-                "email": {
+                "cinema_id": {
+                    "type": "integer"
+                },
+                "cinema_name": {
                     "type": "string"
                 },
-                "first_name": {
+                "duration": {
+                    "type": "integer"
+                },
+                "film_id": {
+                    "type": "integer"
+                },
+                "film_title": {
                     "type": "string"
                 },
-                "login": {
+                "format": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "hall_id": {
+                    "type": "integer"
                 },
-                "phone": {
+                "hall_name": {
                     "type": "string"
                 },
-                "second_name": {
+                "hall_type": {
                     "type": "string"
-                }
-            }
-        },
-        "CinemaBooking_pkg_dt.RegisterDTO": {
-            "type": "object",
-            "properties": {
+                },
                 "id": {
                     "type": "integer"
+                },
+                "late_show": {
+                    "type": "boolean"
+                },
+                "local_start_time": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_from": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "subtitle_language": {
+                    "type": "string"
+                },
+                "surcharge": {
+                    "description": "наценка за формат",
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.ReviewDTO": {
+        "CinemaBooking_pkg_dt.SessionSearchResultDTO": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionSearchItemDTO"
+                    }
                 },
-                "rating": {
+                "limit": {
                     "type": "integer"
                 },
-                "user_id": {
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "CinemaBooking_pkg_dt.SeatDTO": {
+        "CinemaBooking_pkg_dt.SessionSeriesDTO": {
             "type": "object",
             "properties": {
-                "row": {
+                "audio_language": {
+                    "type": "string"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "film_id": {
                     "type": "integer"
                 },
-                "seat": {
+                "format": {
+                    "type": "string"
+                },
+                "hall_id": {
                     "type": "integer"
                 },
-                "state": {
-                    "description": "free / taken",
+                "id": {
+                    "type": "integer"
+                },
+                "late_show": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionDTO"
+                    }
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subtitle_language": {
                     "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "CinemaBooking_pkg_dt.ServAnswerDTO": {
+        "CinemaBooking_pkg_dt.SessionSeriesPreviewDTO": {
             "type": "object",
             "properties": {
-                "answer": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.SeriesOccurrenceDTO"
+                    }
                 }
             }
         },
-        "CinemaBooking_pkg_dt.SessionDTO": {
+        "CinemaBooking_pkg_dt.UpdateHallDTI": {
             "type": "object",
             "properties": {
-                "film_id": {
+                "buffer_minutes": {
+                    "description": "перерыв между сеансами в минутах; на уже созданные сеансы не влияет",
                     "type": "integer"
                 },
-                "hall_id": {
+                "capacity": {
                     "type": "integer"
                 },
-                "id": {
+                "hall_type_id": {
                     "type": "integer"
                 },
-                "price": {
-                    "type": "number"
-                },
-                "start_time": {
+                "name": {
                     "type": "string"
+                },
+                "structure": {
+                    "type": "object"
                 }
             }
         },
@@ -2631,75 +7610,46 @@ const docTemplate = `{
                 }
             }
         },
-        "CinemaBooking_pkg_models.AuthCredential": {
+        "CinemaBooking_pkg_dt.UpdateSessionSeriesDTI": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "deleted_at": {
-                    "type": "string"
-                },
-                "id": {
+                "film_id": {
                     "type": "integer"
                 },
-                "login": {
-                    "type": "string"
+                "price": {
+                    "type": "integer"
                 },
-                "passwordHash": {
+                "prices": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/CinemaBooking_pkg_dt.SessionPriceDTI"
                     }
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
-        "CinemaBooking_pkg_models.BonusHistory": {
+        "CinemaBooking_pkg_dt.ValidateSessionDTI": {
             "type": "object",
+            "required": [
+                "film_id",
+                "hall_id",
+                "start"
+            ],
             "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "deleted_at": {
-                    "type": "string"
-                },
-                "desc": {
-                    "type": "string"
+                "film_id": {
+                    "type": "integer"
                 },
-                "id": {
+                "hall_id": {
                     "type": "integer"
                 },
-                "operation": {
-                    "$ref": "#/definitions/CinemaBooking_pkg_models.BonusOperation"
+                "session_id": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "start": {
+                    "description": "RFC3339 или местное время кинотеатра YYYY-MM-DDTHH:MM",
                     "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/CinemaBooking_pkg_models.User"
-                },
-                "userID": {
-                    "type": "integer"
                 }
             }
         },
-        "CinemaBooking_pkg_models.BonusOperation": {
-            "type": "string",
-            "enum": [
-                "earn",
-                "redeem"
-            ],
-            "x-enum-varnames": [
-                "BonusEarn",
-                "BonusRedeem"
-            ]
-        },
         "CinemaBooking_pkg_models.BookingStatus": {
             "type": "string",
             "enum": [
@@ -2713,86 +7663,170 @@ const docTemplate = `{
                 "BookingCanceled"
             ]
         },
-        "CinemaBooking_pkg_models.Profile": {
+        "CinemaBooking_pkg_models.PaymentMethod": {
+            "type": "string",
+            "enum": [
+                "wallet",
+                "card"
+            ],
+            "x-enum-comments": {
+                "PayCard": "картой через платёжный шлюз",
+                "PayWallet": "с баланса кошелька"
+            },
+            "x-enum-varnames": [
+                "PayWallet",
+                "PayCard"
+            ]
+        },
+        "CinemaBooking_pkg_models.SeatType": {
+            "type": "string",
+            "enum": [
+                "standard",
+                "vip",
+                "couch",
+                "wheelchair"
+            ],
+            "x-enum-comments": {
+                "SeatCouch": "диван на двоих, продаётся одним билетом",
+                "SeatWheelchair": "место для зрителя на коляске"
+            },
+            "x-enum-varnames": [
+                "SeatStandard",
+                "SeatVIP",
+                "SeatCouch",
+                "SeatWheelchair"
+            ]
+        },
+        "CinemaBooking_pkg_money.Currency": {
+            "type": "string",
+            "enum": [
+                "RUB",
+                "RUB"
+            ],
+            "x-enum-varnames": [
+                "RUB",
+                "Default"
+            ]
+        },
+        "CinemaBooking_pkg_payments.Event": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "type": "number"
-                },
-                "birthDay": {
-                    "type": "string"
+                "created": {
+                    "type": "integer"
                 },
-                "bonus": {
-                    "type": "number"
+                "data": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_payments.Intent"
                 },
-                "created_at": {
+                "id": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "type": {
+                    "description": "payment.\u003cстатус\u003e",
                     "type": "string"
+                }
+            }
+        },
+        "CinemaBooking_pkg_payments.Intent": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "currency": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_money.Currency"
                 },
-                "firstName": {
+                "failure_reason": {
                     "type": "string"
                 },
                 "id": {
-                    "type": "integer"
-                },
-                "phone": {
                     "type": "string"
                 },
-                "secondName": {
+                "reference": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
+                "refunded": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_payments.Status"
                 }
             }
         },
-        "CinemaBooking_pkg_models.User": {
+        "CinemaBooking_pkg_payments.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "failed",
+                "refunded"
+            ],
+            "x-enum-comments": {
+                "StatusFailed": "отклонён",
+                "StatusPending": "создан, ждёт подтверждения или обработки",
+                "StatusRefunded": "возвращён полностью",
+                "StatusSucceeded": "деньги списаны с карты"
+            },
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusSucceeded",
+                "StatusFailed",
+                "StatusRefunded"
+            ]
+        },
+        "CinemaBooking_pkg_services.HallRow": {
             "type": "object",
             "properties": {
-                "auth": {
-                    "$ref": "#/definitions/CinemaBooking_pkg_models.AuthCredential"
+                "label": {
+                    "type": "string"
                 },
-                "authID": {
+                "row": {
                     "type": "integer"
                 },
-                "created_at": {
-                    "type": "string"
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_services.HallSeat"
+                    }
+                }
+            }
+        },
+        "CinemaBooking_pkg_services.HallSeat": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "description": "место выведено из продажи",
+                    "type": "boolean"
                 },
-                "deleted_at": {
+                "label": {
                     "type": "string"
                 },
-                "id": {
+                "seat": {
                     "type": "integer"
                 },
-                "profile": {
-                    "$ref": "#/definitions/CinemaBooking_pkg_models.Profile"
-                },
-                "profileID": {
-                    "type": "integer"
+                "type": {
+                    "$ref": "#/definitions/CinemaBooking_pkg_models.SeatType"
                 },
-                "updated_at": {
-                    "type": "string"
+                "x": {
+                    "type": "number"
                 },
-                "userType": {
-                    "$ref": "#/definitions/CinemaBooking_pkg_models.UserType"
+                "y": {
+                    "type": "number"
                 }
             }
         },
-        "CinemaBooking_pkg_models.UserType": {
-            "type": "string",
-            "enum": [
-                "customer",
-                "admin"
-            ],
-            "x-enum-varnames": [
-                "Customer",
-                "Admin"
-            ]
+        "CinemaBooking_pkg_services.HallStructure": {
+            "type": "object",
+            "properties": {
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CinemaBooking_pkg_services.HallRow"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
@@ -3,6 +3,8 @@ package db
 import (
 	"CinemaBooking/config"
 	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"fmt"
 	"log"
 
 	"gorm.io/driver/postgres"
@@ -18,6 +20,7 @@ func InitDB() {
 		NamingStrategy: schema.NamingStrategy{
 			SingularTable: true,
 		},
+		TranslateError: true, // нарушение уникальности → gorm.ErrDuplicatedKey
 	})
 	if err != nil {
 		log.Fatalf("Ошибка подключения к БД: %v", err)
@@ -29,7 +32,6 @@ func InitDB() {
 	}
 
 	DB = db
-	log.Println("Пропуск миграций, не забыть вернуть")
 	log.Println("Успешное выполнение миграций")
 }
 
@@ -42,8 +44,12 @@ func CloseDB() {
 	sqlDB.Close()
 }
 
+// Денежные колонки с самого начала numeric(12,2), а money.Money читает и пишет их как точную
+// десятичную строку, поэтому при переходе с float64 данные не конвертируются и не теряют копеек.
+// Новая колонка валюты платежа заполняется для старых записей значением по умолчанию 'RUB'.
+// Сервис работает в одной валюте (money.Default), поэтому платежи в другой валюте останавливают запуск
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := db.AutoMigrate(
 		&models.AuthCredential{},
 		&models.User{},
 		&models.Profile{},
@@ -59,9 +65,119 @@ func Migrate(db *gorm.DB) error {
 		&models.Review{},
 
 		&models.Session{},
+		&models.SessionPrice{},
+		&models.SessionSeries{},
+		&models.FormatSurcharge{},
+		&models.Order{},
 		&models.Booking{},
+		&models.CancellationPolicy{},
+		&models.CancellationRule{},
 
 		&models.PaymentHistory{},
+		&models.PaymentIntent{},
+		&models.PaymentEvent{},
 		&models.BonusHistory{},
-	)
+		&models.LedgerAccount{},
+		&models.LedgerEntry{},
+		&models.LedgerPosting{},
+		&models.Notification{},
+	); err != nil {
+		return err
+	}
+
+	if err := migrateSeatIndex(db); err != nil {
+		return err
+	}
+	if err := migratePolicyScopeIndex(db); err != nil {
+		return err
+	}
+	if err := migrateSearchIndexes(db); err != nil {
+		return err
+	}
+	if err := checkSingleCurrency(db); err != nil {
+		return err
+	}
+	if err := migratePriceMultiplier(db); err != nil {
+		return err
+	}
+	return migrateLedgerAppendOnly(db)
+}
+
+// Суммы без кода валюты считаются в валюте сервиса; платёж в другой валюте означает, что данные смешаны
+func checkSingleCurrency(db *gorm.DB) error {
+	var other []string
+	if err := db.Model(&models.PaymentIntent{}).
+		Where("currency <> ?", money.Default).
+		Distinct().
+		Pluck("currency", &other).Error; err != nil {
+		return err
+	}
+	if len(other) > 0 {
+		return fmt.Errorf("платежи в валютах %v, а сервис работает только в %s", other, money.Default)
+	}
+	return nil
+}
+
+// Множитель цены категории хранится целым числом базисных пунктов (multiplier_bp) вместо numeric(6,3):
+// старые значения переносятся, после чего старая колонка удаляется
+func migratePriceMultiplier(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.SessionPrice{}, "multiplier") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE session_price SET multiplier_bp = ROUND(multiplier * ?)
+			WHERE multiplier IS NOT NULL AND multiplier_bp IS NULL`, money.BasisPointsPerUnit).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&models.SessionPrice{}, "multiplier")
+	})
+}
+
+// Место занято, только пока бронь активна: старый уникальный индекс по всем броням
+// не давал снова продать место после отмены или снятия просроченной брони, поэтому он заменён частичным.
+func migrateSeatIndex(db *gorm.DB) error {
+	if err := db.Exec(`DROP INDEX IF EXISTS idx_seat`).Error; err != nil {
+		return err
+	}
+
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_seat_active
+		ON booking (session_id, row_num, seat_num)
+		WHERE status IN ('reserved', 'paid')`).Error
+}
+
+// Одна действующая политика отмены на область (кинотеатр, тип зала); NULL означает «любой»,
+// поэтому сравнение идёт через COALESCE, а удалённые политики в индекс не попадают.
+func migratePolicyScopeIndex(db *gorm.DB) error {
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_cancellation_policy_scope
+		ON cancellation_policy (COALESCE(cinema_id, 0), COALESCE(hall_type_id, 0))
+		WHERE deleted_at IS NULL`).Error
+}
+
+// Индекс связей фильм-жанр по жанру для фильтра по жанрам: таблица создаётся связью many2many,
+// поэтому индекс задаётся здесь, а не тегом модели
+func migrateSearchIndexes(db *gorm.DB) error {
+	return db.Exec(`CREATE INDEX IF NOT EXISTS idx_film_genres_genre ON film_genres (genre_id)`).Error
+}
+
+// Журнал проводок только дополняется: изменить или удалить проводку и её движения не даёт сама база
+func migrateLedgerAppendOnly(db *gorm.DB) error {
+	if err := db.Exec(`CREATE OR REPLACE FUNCTION ledger_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'журнал проводок только дополняется: % в % запрещён', TG_OP, TG_TABLE_NAME;
+		END
+		$$ LANGUAGE plpgsql`).Error; err != nil {
+		return err
+	}
+
+	for _, table := range []string{"ledger_entry", "ledger_posting"} {
+		if err := db.Exec(`DROP TRIGGER IF EXISTS ` + table + `_append_only ON ` + table).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(`CREATE TRIGGER ` + table + `_append_only
+			BEFORE UPDATE OR DELETE ON ` + table + `
+			FOR EACH ROW EXECUTE FUNCTION ledger_append_only()`).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -2,6 +2,8 @@ package dt
 
 import (
 	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"encoding/json"
 	"time"
 )
 
@@ -41,12 +43,22 @@ type LoginDTO struct {
 
 // BonusBalanceDTO godoc
 type BonusBalanceDTO struct {
-	Balance float64 `json:"balance"`
+	Balance money.Money `json:"balance"`
 }
 
 // BonusHistoryDTO godoc
 type BonusHistoryDTO struct {
-	HistoryBalance []models.BonusHistory `json:"history_balance"`
+	HistoryBalance []BonusHistoryItemDTO `json:"history_balance"`
+}
+
+// BonusHistoryItemDTO godoc
+// движение по бонусному счёту: плюс — начисление, минус — списание
+type BonusHistoryItemDTO struct {
+	ID        uint        `json:"id"`
+	Amount    money.Money `json:"amount"`
+	Desc      string      `json:"desc"`
+	Operation string      `json:"operation"`
+	CreatedAt time.Time   `json:"created_at"`
 }
 
 // CreateBookingDTI godoc
@@ -56,12 +68,208 @@ type CreateBookingDTI struct {
 	RowNum    uint `json:"row_num" binding:"required"`
 	SeatNum   uint `json:"seat_num" binding:"required"`
 	UseBonus  bool `json:"use_bonus" binding:"required"`
+	// wallet (по умолчанию) — списать с баланса сразу, card — удержать место и оплатить картой через шлюз
+	PaymentMethod models.PaymentMethod `json:"payment_method" binding:"omitempty,oneof=wallet card"`
 }
 
 // BookingDTO godoc
 type CreateBookingDTO struct {
-	ID     uint                 `json:"status_id"`
-	Status models.BookingStatus `json:"status"`
+	ID        uint                 `json:"status_id"`
+	Status    models.BookingStatus `json:"status"`
+	ExpiresAt *time.Time           `json:"expires_at,omitempty"` // до какого момента место ждёт оплаты картой
+	Payment   *BookingPaymentDTO   `json:"payment,omitempty"`
+	Warning   string               `json:"warning,omitempty"` // возрастное предупреждение кинотеатра
+}
+
+// BookingPaymentDTO godoc
+// оплата брони картой: pending — ждёт подтверждения, succeeded — бронь оплачена, failed — отклонена и место снято,
+// refund_pending — оплата пришла, когда место уже было снято, возврат на карту выполняется,
+// refunded — деньги возвращены на карту
+type BookingPaymentDTO struct {
+	ID            uint           `json:"id"`
+	BookingID     uint           `json:"booking_id"`
+	Amount        money.Money    `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+	Status        string         `json:"status"`
+	FailureReason string         `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+}
+
+// HoldSeatDTI godoc
+type HoldSeatDTI struct {
+	UserID    uint `json:"-"`
+	SessionID uint `json:"session_id" binding:"required"`
+	RowNum    uint `json:"row_num" binding:"required"`
+	SeatNum   uint `json:"seat_num" binding:"required"`
+}
+
+// HoldSeatDTO godoc
+type HoldSeatDTO struct {
+	ID        uint                 `json:"id"`
+	Status    models.BookingStatus `json:"status"`
+	ExpiresAt time.Time            `json:"expires_at"`
+	Warning   string               `json:"warning,omitempty"` // возрастное предупреждение кинотеатра
+}
+
+// ConfirmBookingDTI godoc
+type ConfirmBookingDTI struct {
+	UseBonus bool `json:"use_bonus"`
+}
+
+// SeatDTI godoc
+type SeatDTI struct {
+	RowNum  uint `json:"row_num" binding:"required"`
+	SeatNum uint `json:"seat_num" binding:"required"`
+}
+
+// CreateOrderDTI godoc
+type CreateOrderDTI struct {
+	UserID    uint      `json:"-"`
+	SessionID uint      `json:"session_id" binding:"required"`
+	Seats     []SeatDTI `json:"seats" binding:"required,min=1,dive"`
+	UseBonus  bool      `json:"use_bonus"`
+}
+
+// OrderDTO godoc
+type OrderDTO struct {
+	ID            uint                 `json:"id"`
+	SessionID     uint                 `json:"session_id"`
+	Status        models.BookingStatus `json:"status"`
+	SpendBonus    money.Money          `json:"spend_bonus"`
+	ReceivedBonus money.Money          `json:"received_bonus"`
+	TotalPrice    money.Money          `json:"total_price"`
+	Bookings      []OrderBookingDTO    `json:"bookings"`
+	Warning       string               `json:"warning,omitempty"` // возрастное предупреждение кинотеатра
+}
+
+// OrderBookingDTO godoc
+type OrderBookingDTO struct {
+	ID      uint `json:"id"`
+	RowNum  uint `json:"row_num"`
+	SeatNum uint `json:"seat_num"`
+}
+
+// BookingFilterDTI godoc
+// status: upcoming / past / cancelled (пусто — все)
+type BookingFilterDTI struct {
+	Status string `form:"status"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+// BookingDetailDTO godoc
+type BookingDetailDTO struct {
+	ID             uint                 `json:"id"`
+	OrderID        *uint                `json:"order_id,omitempty"`
+	SessionID      uint                 `json:"session_id"`
+	FilmID         uint                 `json:"film_id"`
+	FilmTitle      string               `json:"film_title"`
+	HallID         uint                 `json:"hall_id"`
+	HallName       string               `json:"hall_name"`
+	CinemaID       uint                 `json:"cinema_id"`
+	CinemaName     string               `json:"cinema_name"`
+	StartTime      time.Time            `json:"start_time"`
+	LocalStartTime time.Time            `json:"local_start_time"`
+	RowNum         uint                 `json:"row_num"`
+	SeatNum        uint                 `json:"seat_num"`
+	SpendBonus     money.Money          `json:"spend_bonus"`
+	ReceivedBonus  money.Money          `json:"received_bonus"`
+	TotalPrice     money.Money          `json:"total_price"`
+	RefundedAmount money.Money          `json:"refunded_amount"`
+	Status         models.BookingStatus `json:"status"`
+	ExpiresAt      *time.Time           `json:"expires_at,omitempty"`
+	PaymentMethod  models.PaymentMethod `json:"payment_method"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
+// BookingListDTO godoc
+type BookingListDTO struct {
+	Items []BookingDetailDTO `json:"items"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int64              `json:"total"`
+}
+
+// AdminBookingFilterDTI godoc
+// date_from / date_to — дата сеанса в формате YYYY-MM-DD по местному времени кинотеатра
+type AdminBookingFilterDTI struct {
+	SessionID uint   `form:"session_id"`
+	UserID    uint   `form:"user_id"`
+	Status    string `form:"status"`
+	DateFrom  string `form:"date_from"`
+	DateTo    string `form:"date_to"`
+	Page      int    `form:"page"`
+	Limit     int    `form:"limit"`
+}
+
+// AdminBookingDTO godoc
+type AdminBookingDTO struct {
+	BookingDetailDTO
+	CustomerID   uint       `json:"customer_id"`
+	CanceledAt   *time.Time `json:"canceled_at,omitempty"`
+	CanceledBy   *uint      `json:"canceled_by,omitempty"`
+	CancelReason string     `json:"cancel_reason,omitempty"`
+}
+
+// AdminBookingListDTO godoc
+type AdminBookingListDTO struct {
+	Items []AdminBookingDTO `json:"items"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+	Total int64             `json:"total"`
+}
+
+// AdminCancelBookingDTI godoc
+type AdminCancelBookingDTI struct {
+	Reason string `json:"reason" binding:"required"`
+	Refund bool   `json:"refund"`
+}
+
+// RefundPreviewDTO godoc
+type RefundPreviewDTO struct {
+	RefundPercent uint        `json:"refund_percent"`
+	RefundAmount  money.Money `json:"refund_amount"`
+	BonusReturn   money.Money `json:"bonus_return"`
+	BonusClawback money.Money `json:"bonus_clawback"`
+	PolicyID      *uint       `json:"policy_id,omitempty"`
+	PolicyName    string      `json:"policy_name"`
+}
+
+// CancellationRuleDTI godoc
+type CancellationRuleDTI struct {
+	MinHoursBefore float64 `json:"min_hours_before" binding:"min=0"`
+	RefundPercent  uint    `json:"refund_percent" binding:"max=100"`
+}
+
+// CreateCancellationPolicyDTI godoc
+// без cinema_id и hall_type_id политика действует для всех залов
+type CreateCancellationPolicyDTI struct {
+	Name       string                `json:"name" binding:"required"`
+	CinemaID   *uint                 `json:"cinema_id"`
+	HallTypeID *uint                 `json:"hall_type_id"`
+	Rules      []CancellationRuleDTI `json:"rules" binding:"required,min=1,dive"`
+}
+
+// CreateCancellationPolicyDTO godoc
+type CreateCancellationPolicyDTO struct {
+	ID uint `json:"id"`
+}
+
+// CancellationRuleDTO godoc
+type CancellationRuleDTO struct {
+	MinHoursBefore float64 `json:"min_hours_before"`
+	RefundPercent  uint    `json:"refund_percent"`
+}
+
+// CancellationPolicyDTO godoc
+// правила по убыванию срока
+type CancellationPolicyDTO struct {
+	ID         uint                  `json:"id"`
+	Name       string                `json:"name"`
+	CinemaID   *uint                 `json:"cinema_id"`
+	HallTypeID *uint                 `json:"hall_type_id"`
+	Rules      []CancellationRuleDTO `json:"rules"`
 }
 
 // GetFilmDTO godoc
@@ -80,20 +288,37 @@ type GenreDTO struct {
 }
 
 // PaymentHistoryDTO godoc
+// движение по балансу: плюс — зачисление, минус — списание
 type PaymentHistoryDTO struct {
-	Amount    float64   `json:"amount"`
-	Operation string    `json:"operation"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        uint        `json:"id"`
+	Amount    money.Money `json:"amount"`
+	Desc      string      `json:"desc"`
+	Operation string      `json:"operation"`
+	CreatedAt time.Time   `json:"created_at"`
 }
 
 // PaymentDTO godoc
 type PaymentDTO struct {
-	Balance float64 `json:"balance"`
+	Balance  money.Money    `json:"balance"`
+	Currency money.Currency `json:"currency"`
 }
 
 // RefillBalanceDTI godoc
 type RefillBalanceDTI struct {
-	Amount float64 `json:"amount" binding:"required"`
+	Amount   money.Money    `json:"amount" binding:"required"`
+	Currency money.Currency `json:"currency"` // если не указана — валюта сервиса
+}
+
+// RefillDTO godoc
+// пополнение через платёжный шлюз: pending — ждёт подтверждения, succeeded — зачислено, failed — отклонено
+type RefillDTO struct {
+	ID            uint           `json:"id"`
+	Amount        money.Money    `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+	Status        string         `json:"status"`
+	FailureReason string         `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
 }
 
 // PosterDTO godoc
@@ -104,11 +329,12 @@ type PosterDTO struct {
 
 // ProfileDTO godoc
 type ProfileDTO struct {
-	FirstName  string  `json:"first_name"`
-	SecondName string  `json:"second_name"`
-	Email      string  `json:"email"`
-	Balance    float64 `json:"balance"`
-	Bonus      float64 `json:"bonus"`
+	FirstName  string         `json:"first_name"`
+	SecondName string         `json:"second_name"`
+	Email      string         `json:"email"`
+	Balance    money.Money    `json:"balance"`
+	Bonus      money.Money    `json:"bonus"`
+	Currency   money.Currency `json:"currency"` // валюта баланса
 }
 
 // ChangePasswordDTI godoc
@@ -159,19 +385,50 @@ type FilmRatingDTO struct {
 }
 
 // SessionDTO godoc
+// start_time — в UTC, local_start_time — то же время в поясе кинотеатра
 type SessionDTO struct {
-	ID        uint      `json:"id"`
-	FilmID    uint      `json:"film_id"`
-	HallID    uint      `json:"hall_id"`
-	StartTime time.Time `json:"start_time"`
-	Price     float64   `json:"price"`
+	ID             uint        `json:"id"`
+	FilmID         uint        `json:"film_id"`
+	HallID         uint        `json:"hall_id"`
+	StartTime      time.Time   `json:"start_time"`
+	LocalStartTime time.Time   `json:"local_start_time"`
+	Timezone       string      `json:"timezone"`
+	Price          money.Money `json:"price"`
+	Surcharge      money.Money `json:"surcharge"` // наценка за формат
+	SessionAttributesDTO
+}
+
+// SessionAttributesDTO godoc
+type SessionAttributesDTO struct {
+	Format           string `json:"format"`
+	AudioLanguage    string `json:"audio_language"`
+	SubtitleLanguage string `json:"subtitle_language,omitempty"`
+	LateShow         bool   `json:"late_show"`
+}
+
+// SessionFilterDTI godoc
+// фильтры списка сеансов; subtitles=none — только без субтитров;
+// allowed_only=true — только сеансы, на которые текущему пользователю можно по возрасту (нужна авторизация)
+type SessionFilterDTI struct {
+	Format      string `form:"format"`
+	Audio       string `form:"audio"`
+	Subtitles   string `form:"subtitles"`
+	LateShow    *bool  `form:"late_show"`
+	AllowedOnly bool   `form:"allowed_only"`
+	ViewerID    uint   `form:"-"`
 }
 
 // SeatDTO godoc
 type SeatDTO struct {
-	Row   uint   `json:"row"`
-	Seat  uint   `json:"seat"`
-	State string `json:"state"` // free / taken
+	Row      uint        `json:"row"`
+	RowLabel string      `json:"row_label"`
+	Seat     uint        `json:"seat"`
+	Label    string      `json:"label"`
+	Type     string      `json:"type"` // standard / vip / couch / wheelchair
+	X        float64     `json:"x"`
+	Y        float64     `json:"y"`
+	Price    money.Money `json:"price"`
+	State    string      `json:"state"` // free / taken / blocked
 }
 
 // ServAnswerDTO godoc
@@ -212,10 +469,49 @@ type AssignGenreDTI struct {
 
 // CreateSessionDTI godoc
 type CreateSessionDTI struct {
-	FilmID uint      `json:"film_id" binding:"required"`
-	HallID uint      `json:"hall_id" binding:"required"`
-	Start  time.Time `json:"start" binding:"required"`
-	Price  float64   `json:"price" binding:"required"`
+	FilmID    uint              `json:"film_id" binding:"required"`
+	HallID    uint              `json:"hall_id" binding:"required"`
+	Start     string            `json:"start" binding:"required"` // RFC3339 или местное время кинотеатра YYYY-MM-DDTHH:MM
+	Price     money.Money       `json:"price" binding:"required"` // базовая цена (обычное место)
+	Prices    []SessionPriceDTI `json:"prices"`
+	Surcharge *money.Money      `json:"surcharge"` // если не указана — берётся наценка формата
+	SessionAttributesDTI
+}
+
+// SessionAttributesDTI godoc
+// параметры показа: format — 2d, 3d, imax, imax_3d (по умолчанию 2d);
+// языки — коды ISO 639-1 (по умолчанию звук ru, без субтитров); late_show — поздний сеанс 18+
+type SessionAttributesDTI struct {
+	Format           string `json:"format"`
+	AudioLanguage    string `json:"audio_language"`
+	SubtitleLanguage string `json:"subtitle_language"`
+	LateShow         bool   `json:"late_show"`
+}
+
+// FormatSurchargeDTI godoc
+type FormatSurchargeDTI struct {
+	Amount money.Money `json:"amount" binding:"min=0"`
+}
+
+// FormatSurchargeDTO godoc
+type FormatSurchargeDTO struct {
+	Format string      `json:"format"`
+	Amount money.Money `json:"amount"`
+}
+
+// SessionPriceDTI godoc
+// цена категории мест: своя цена или множитель к базовой
+type SessionPriceDTI struct {
+	SeatType   string       `json:"seat_type" binding:"required"`
+	Price      *money.Money `json:"price"`
+	Multiplier *float64     `json:"multiplier"`
+}
+
+// SessionPriceDTO godoc
+type SessionPriceDTO struct {
+	SeatType   string      `json:"seat_type"`
+	Price      money.Money `json:"price"`
+	Multiplier *float64    `json:"multiplier,omitempty"`
 }
 
 // CreateSessionDTO godoc
@@ -233,3 +529,331 @@ type CreatePosterDTI struct {
 type CreatePosterDTO struct {
 	ID uint `json:"id"`
 }
+
+// CreateCinemaDTI godoc
+type CreateCinemaDTI struct {
+	Name     string `json:"name" binding:"required"`
+	Location string `json:"location"`
+	Phone    string `json:"phone" binding:"omitempty,max=11"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Timezone string `json:"timezone"` // часовой пояс IANA, по умолчанию Europe/Moscow
+	// при возрасте ниже ограничения: block — не продавать (по умолчанию), warn — продать с предупреждением
+	AgeCheck string `json:"age_check" binding:"omitempty,oneof=block warn"`
+}
+
+// CreateCinemaDTO godoc
+type CreateCinemaDTO struct {
+	ID uint `json:"id"`
+}
+
+// CreateHallTypeDTI godoc
+type CreateHallTypeDTI struct {
+	Name string `json:"name" binding:"required"`
+	Desc string `json:"desc"`
+}
+
+// CreateHallTypeDTO godoc
+type CreateHallTypeDTO struct {
+	ID uint `json:"id"`
+}
+
+// CreateHallDTI godoc
+type CreateHallDTI struct {
+	HallTypeID uint            `json:"hall_type_id" binding:"required"`
+	Name       string          `json:"name" binding:"required"`
+	Capacity   uint            `json:"capacity" binding:"required"`
+	Structure  json.RawMessage `json:"structure" binding:"required" swaggertype:"object"`
+	// перерыв между сеансами в минутах; не задан — значение по умолчанию
+	BufferMinutes *uint `json:"buffer_minutes"`
+}
+
+// UpdateHallDTI godoc
+// незаданные поля не меняются
+type UpdateHallDTI struct {
+	HallTypeID *uint           `json:"hall_type_id"`
+	Name       *string         `json:"name"`
+	Capacity   *uint           `json:"capacity"`
+	Structure  json.RawMessage `json:"structure" swaggertype:"object"`
+	// перерыв между сеансами в минутах; на уже созданные сеансы не влияет
+	BufferMinutes *uint `json:"buffer_minutes"`
+}
+
+// CreateHallDTO godoc
+type CreateHallDTO struct {
+	ID uint `json:"id"`
+}
+
+// CinemaDTO godoc
+type CinemaDTO struct {
+	ID       uint            `json:"id"`
+	Name     string          `json:"name"`
+	Location string          `json:"location"`
+	Phone    string          `json:"phone"`
+	Email    string          `json:"email"`
+	Timezone string          `json:"timezone"`
+	AgeCheck string          `json:"age_check"`
+	Halls    []CinemaHallDTO `json:"halls,omitempty"`
+}
+
+// CinemaHallDTO godoc
+type CinemaHallDTO struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	HallType string `json:"hall_type"`
+	Capacity uint   `json:"capacity"`
+}
+
+// HallTypeDTO godoc
+type HallTypeDTO struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Desc string `json:"desc"`
+}
+
+// HallDTO godoc
+// зал со схемой для администратора; buffer_minutes не задан — перерыв по умолчанию
+type HallDTO struct {
+	ID            uint            `json:"id"`
+	CinemaID      uint            `json:"cinema_id"`
+	HallTypeID    uint            `json:"hall_type_id"`
+	HallType      string          `json:"hall_type"`
+	Name          string          `json:"name"`
+	Capacity      uint            `json:"capacity"`
+	Structure     json.RawMessage `json:"structure" swaggertype:"object"`
+	BufferMinutes *uint           `json:"buffer_minutes"`
+}
+
+// CinemaScheduleDTO godoc
+// расписание кинотеатра на день: фильм -> зал -> сеансы
+type CinemaScheduleDTO struct {
+	CinemaID   uint              `json:"cinema_id"`
+	CinemaName string            `json:"cinema_name"`
+	Timezone   string            `json:"timezone"`
+	Date       string            `json:"date"`
+	Films      []ScheduleFilmDTO `json:"films"`
+}
+
+// ScheduleFilmDTO godoc
+type ScheduleFilmDTO struct {
+	FilmID    uint              `json:"film_id"`
+	Title     string            `json:"title"`
+	Duration  uint              `json:"duration"`
+	AgeRating uint              `json:"age_rating"`
+	Halls     []ScheduleHallDTO `json:"halls"`
+}
+
+// ScheduleHallDTO godoc
+type ScheduleHallDTO struct {
+	HallID   uint                 `json:"hall_id"`
+	HallName string               `json:"hall_name"`
+	HallType string               `json:"hall_type"`
+	Sessions []ScheduleSessionDTO `json:"sessions"`
+}
+
+// ScheduleSessionDTO godoc
+type ScheduleSessionDTO struct {
+	ID             uint        `json:"id"`
+	StartTime      time.Time   `json:"start_time"`
+	LocalStartTime time.Time   `json:"local_start_time"`
+	Price          money.Money `json:"price"`
+	Surcharge      money.Money `json:"surcharge"`
+	SessionAttributesDTO
+}
+
+// ValidateSessionDTI godoc
+// проверка сеанса на пересечения без сохранения; session_id — при переносе существующего сеанса
+type ValidateSessionDTI struct {
+	SessionID *uint  `json:"session_id"`
+	FilmID    uint   `json:"film_id" binding:"required"`
+	HallID    uint   `json:"hall_id" binding:"required"`
+	Start     string `json:"start" binding:"required"` // RFC3339 или местное время кинотеатра YYYY-MM-DDTHH:MM
+}
+
+// ScheduleCheckDTO godoc
+// end — время, до которого зал занят с учётом перерыва; время — в поясе кинотеатра
+type ScheduleCheckDTO struct {
+	OK          bool      `json:"ok"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	ConflictIDs []uint    `json:"conflict_ids"`
+}
+
+// ScheduleConflictResponse godoc
+type ScheduleConflictResponse struct {
+	Code        string `json:"code"`
+	Message     string `json:"error"`
+	ConflictIDs []uint `json:"conflict_ids"`
+}
+
+// CreateSessionSeriesDTI godoc
+// шаблон расписания: слоты HH:MM по местному времени кинотеатра, дни недели 1–7 (1 — понедельник), даты YYYY-MM-DD включительно
+type CreateSessionSeriesDTI struct {
+	FilmID   uint              `json:"film_id" binding:"required"`
+	HallID   uint              `json:"hall_id" binding:"required"`
+	Slots    []string          `json:"slots" binding:"required,min=1"`
+	Weekdays []int             `json:"weekdays" binding:"required,min=1,dive,min=1,max=7"`
+	DateFrom string            `json:"date_from" binding:"required"`
+	DateTo   string            `json:"date_to" binding:"required"`
+	Price    money.Money       `json:"price" binding:"required"`
+	Prices   []SessionPriceDTI `json:"prices"`
+	SessionAttributesDTI
+}
+
+// CreateSessionSeriesDTO godoc
+type CreateSessionSeriesDTO struct {
+	ID         uint   `json:"id"`
+	SessionIDs []uint `json:"session_ids"`
+}
+
+// SeriesOccurrenceDTO godoc
+// сеанс серии во времени кинотеатра; conflict_ids — существующие сеансы зала, с которыми он пересекается
+type SeriesOccurrenceDTO struct {
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	ConflictIDs []uint    `json:"conflict_ids"`
+}
+
+// SessionSeriesPreviewDTO godoc
+type SessionSeriesPreviewDTO struct {
+	OK       bool                  `json:"ok"`
+	Count    int                   `json:"count"`
+	Sessions []SeriesOccurrenceDTO `json:"sessions"`
+}
+
+// SessionSeriesDTO godoc
+type SessionSeriesDTO struct {
+	ID       uint        `json:"id"`
+	FilmID   uint        `json:"film_id"`
+	HallID   uint        `json:"hall_id"`
+	Slots    []string    `json:"slots"`
+	Weekdays []int       `json:"weekdays"`
+	DateFrom string      `json:"date_from"`
+	DateTo   string      `json:"date_to"`
+	Price    money.Money `json:"price"`
+	SessionAttributesDTO
+	Sessions []SessionDTO `json:"sessions,omitempty"`
+}
+
+// UpdateSessionSeriesDTI godoc
+// применяется к ещё не начавшимся сеансам серии; незаданные поля не меняются, prices: [] сбрасывает цены категорий
+type UpdateSessionSeriesDTI struct {
+	FilmID *uint             `json:"film_id"`
+	Price  *money.Money      `json:"price"`
+	Prices []SessionPriceDTI `json:"prices"`
+}
+
+// SeriesUpdateResultDTO godoc
+type SeriesUpdateResultDTO struct {
+	Updated int `json:"updated"`
+}
+
+// SeriesDeleteResultDTO godoc
+// kept_ids — сеансы с действующими бронями, которые не были удалены
+type SeriesDeleteResultDTO struct {
+	Deleted int    `json:"deleted"`
+	KeptIDs []uint `json:"kept_ids"`
+}
+
+// CancelSessionDTI godoc
+type CancelSessionDTI struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// SessionCancelSummaryDTO godoc
+// итог отмены сеанса: сколько мест снято и сколько вернули клиентам
+type SessionCancelSummaryDTO struct {
+	SessionID     uint                `json:"session_id"`
+	Reason        string              `json:"reason"`
+	Bookings      int                 `json:"bookings"`       // отменено мест
+	Orders        int                 `json:"orders"`         // отменено заказов
+	Customers     int                 `json:"customers"`      // уведомлено клиентов
+	RefundedMoney money.Money         `json:"refunded_money"` // возвращено денег на балансы
+	BonusReturned money.Money         `json:"bonus_returned"` // возвращено потраченных бонусов
+	BonusClawback money.Money         `json:"bonus_clawback"` // снято начисленных бонусов
+	Refunds       []CustomerRefundDTO `json:"refunds"`
+}
+
+// CustomerRefundDTO godoc
+type CustomerRefundDTO struct {
+	UserID        uint        `json:"user_id"`
+	Seats         int         `json:"seats"`
+	Money         money.Money `json:"money"`
+	BonusReturned money.Money `json:"bonus_returned"`
+	BonusClawback money.Money `json:"bonus_clawback"`
+}
+
+// NotificationDTO godoc
+type NotificationDTO struct {
+	ID        uint      `json:"id"`
+	Kind      string    `json:"kind"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionSearchDTI godoc
+// даты YYYY-MM-DD включительно (по умолчанию — от сегодня на 2 месяца), время HH:MM;
+// даты и время — местные для каждого кинотеатра.
+// окно может переходить через полночь (time_from=22:00&time_to=02:00).
+// genres — ID жанров через запятую, фильм должен иметь все; max_age — потолок возрастного рейтинга;
+// price_min / price_max — цена обычного места с наценкой; adjacent_seats — сколько свободных мест подряд нужно.
+// sort — start_time (по умолчанию), price, title; order — asc, desc
+type SessionSearchDTI struct {
+	DateFrom      string       `form:"date_from"`
+	DateTo        string       `form:"date_to"`
+	TimeFrom      string       `form:"time_from"`
+	TimeTo        string       `form:"time_to"`
+	CinemaID      uint         `form:"cinema_id"`
+	HallTypeID    uint         `form:"hall_type_id"`
+	FilmID        uint         `form:"film_id"`
+	Genres        string       `form:"genres"`
+	MaxAge        *uint        `form:"max_age"`
+	PriceMin      *money.Money `form:"price_min"`
+	PriceMax      *money.Money `form:"price_max"`
+	AdjacentSeats int          `form:"adjacent_seats" binding:"min=0"`
+	SessionFilterDTI
+	Sort  string `form:"sort"`
+	Order string `form:"order"`
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+}
+
+// SessionSearchItemDTO godoc
+// price_from — цена обычного места с наценкой за формат
+type SessionSearchItemDTO struct {
+	SessionDTO
+	PriceFrom  money.Money `json:"price_from"`
+	FilmTitle  string      `json:"film_title"`
+	AgeRating  uint        `json:"age_rating"`
+	Duration   uint        `json:"duration"`
+	CinemaID   uint        `json:"cinema_id"`
+	CinemaName string      `json:"cinema_name"`
+	HallName   string      `json:"hall_name"`
+	HallType   string      `json:"hall_type"`
+}
+
+// SessionSearchResultDTO godoc
+type SessionSearchResultDTO struct {
+	Items []SessionSearchItemDTO `json:"items"`
+	Page  int                    `json:"page"`
+	Limit int                    `json:"limit"`
+	Total int64                  `json:"total"`
+}
+
+// LedgerCheckDTO godoc
+// результат сверки журнала проводок
+type LedgerCheckDTO struct {
+	CheckedAt         time.Time              `json:"checked_at"`
+	Consistent        bool                   `json:"consistent"`
+	UnbalancedEntries []uint                 `json:"unbalanced_entries"` // проводки, движения которых не сходятся в ноль
+	Drift             []LedgerDriftDTO       `json:"drift"`              // расхождения кэша в профиле с журналом
+	SystemBalances    map[string]money.Money `json:"system_balances"`    // остатки общих счетов: выручка, возвраты, шлюз и т.д.
+}
+
+// LedgerDriftDTO godoc
+type LedgerDriftDTO struct {
+	UserID  uint        `json:"user_id"`
+	Account string      `json:"account"` // wallet или bonus
+	Cached  money.Money `json:"cached"`  // баланс в профиле
+	Ledger  money.Money `json:"ledger"`  // сумма движений в журнале
+}
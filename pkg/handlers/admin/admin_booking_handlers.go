@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,21 +11,71 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// SearchBookingsHandler godoc
+// @Summary Найти бронирования
+// @Tags admin-bookings
+// @Security BearerAuth
+// @Produce json
+// @Param session_id query int false "ID сеанса"
+// @Param user_id query int false "ID пользователя"
+// @Param status query string false "reserved / paid / canceled"
+// @Param date_from query string false "Дата сеанса с (YYYY-MM-DD)"
+// @Param date_to query string false "Дата сеанса по (YYYY-MM-DD)"
+// @Param page query int false "Номер страницы (с 1)"
+// @Param limit query int false "Размер страницы (до 100)"
+// @Success 200 {object} dt.AdminBookingListDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/bookings [get]
+func SearchBookingsHandler(c *gin.Context) {
+	var filter dt.AdminBookingFilterDTI
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	bookings, err := services.SearchBookings(filter)
+	if err != nil {
+		if err.Error() == "неверный формат даты, ожидается YYYY-MM-DD" {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, bookings)
+}
+
 // CancelBookingHandler godoc
-// @Summary Отменить бронирование
-// @Tags bookings
+// @Summary Принудительно отменить бронирование клиента
+// @Tags admin-bookings
 // @Security BearerAuth
+// @Accept json
 // @Produce json
 // @Param id path int true "ID бронирования"
+// @Param input body dt.AdminCancelBookingDTI true "Причина отмены и необходимость возврата"
 // @Success 200 {object} dt.ServAnswerDTO
 // @Failure 400 {object} dt.ErrorResponse
 // @Failure 401 {object} dt.ErrorResponse
+// @Failure 402 {object} dt.ErrorResponse
 // @Failure 403 {object} dt.ErrorResponse
 // @Failure 404 {object} dt.ErrorResponse
 // @Failure 500 {object} dt.ErrorResponse
-// @Router /bookings/{id} [delete]
+// @Router /admin/bookings/{id}/cancel [post]
 func CancelBookingHandler(c *gin.Context) {
-	userID, ok := c.Get("user_id")
+	adminID, ok := c.Get("user_id")
 	if !ok {
 		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
 			Code:    "NOT_FOUND",
@@ -43,17 +94,33 @@ func CancelBookingHandler(c *gin.Context) {
 		return
 	}
 
-	err = services.CancelBooking(uint(bookingID), userID.(uint))
+	var input dt.AdminCancelBookingDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = services.AdminCancelBooking(uint(bookingID), adminID.(uint), input)
 	if err != nil {
+		if errors.Is(err, services.ErrInsufficientFunds) || errors.Is(err, services.ErrInsufficientBonus) {
+			c.JSON(http.StatusPaymentRequired, dt.ErrorResponse{
+				Code:    "INSUFFICIENT_FUNDS",
+				Message: err.Error(),
+			})
+			return
+		}
 		switch err.Error() {
 		case "бронирование не найдено":
 			c.JSON(http.StatusNotFound, dt.ErrorResponse{
 				Code:    "NOT_FOUND",
 				Message: err.Error(),
 			})
-		case "нельзя отменить чужое бронирование":
-			c.JSON(http.StatusForbidden, dt.ErrorResponse{
-				Code:    "FORBIDDEN",
+		case "необходимо указать причину отмены":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
 				Message: err.Error(),
 			})
 		case "бронирование нельзя отменить":
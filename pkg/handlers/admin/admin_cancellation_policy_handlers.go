@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCancellationPoliciesHandler godoc
+// @Summary Получить политики отмены
+// @Tags admin-cancellation-policies
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dt.CancellationPolicyDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cancellation-policies [get]
+func GetCancellationPoliciesHandler(c *gin.Context) {
+	policies, err := services.GetCancellationPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result := make([]dt.CancellationPolicyDTO, 0, len(policies))
+	for _, policy := range policies {
+		item := dt.CancellationPolicyDTO{
+			ID:         policy.ID,
+			Name:       policy.Name,
+			CinemaID:   policy.CinemaID,
+			HallTypeID: policy.HallTypeID,
+			Rules:      make([]dt.CancellationRuleDTO, 0, len(policy.Rules)),
+		}
+		for _, rule := range policy.Rules {
+			item.Rules = append(item.Rules, dt.CancellationRuleDTO{
+				MinHoursBefore: rule.MinHoursBefore,
+				RefundPercent:  rule.RefundPercent,
+			})
+		}
+		result = append(result, item)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateCancellationPolicyHandler godoc
+// @Summary Создать политику отмены
+// @Tags admin-cancellation-policies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.CreateCancellationPolicyDTI true "Политика и её правила"
+// @Success 201 {object} dt.CreateCancellationPolicyDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cancellation-policies [post]
+func CreateCancellationPolicyHandler(c *gin.Context) {
+	var input dt.CreateCancellationPolicyDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dto, err := services.CreateCancellationPolicy(input)
+	if err != nil {
+		switch err.Error() {
+		case "процент возврата должен быть от 0 до 100",
+			"правила политики не должны повторять один и тот же срок",
+			"кинотеатр не найден",
+			"тип зала не найден":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+		case "политика для этого кинотеатра и типа зала уже существует":
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "POLICY_EXISTS",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto)
+}
+
+// DeleteCancellationPolicyHandler godoc
+// @Summary Удалить политику отмены
+// @Tags admin-cancellation-policies
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID политики"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cancellation-policies/{id} [delete]
+func DeleteCancellationPolicyHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid policy ID",
+		})
+		return
+	}
+
+	if err := services.DeleteCancellationPolicy(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: "policy not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "политика отмены удалена",
+	})
+}
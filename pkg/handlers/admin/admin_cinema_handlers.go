@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCinemasHandler godoc
+// @Summary Получить все кинотеатры
+// @Tags admin-cinemas
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dt.CinemaDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas [get]
+func GetCinemasHandler(c *gin.Context) {
+	cinemas, err := services.GetCinemas()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result := make([]dt.CinemaDTO, 0, len(cinemas))
+	for _, cinema := range cinemas {
+		result = append(result, dt.CinemaDTO{
+			ID:       cinema.ID,
+			Name:     cinema.Name,
+			Location: cinema.Location,
+			Phone:    cinema.Phone,
+			Email:    cinema.Email,
+			Timezone: cinema.Timezone,
+			AgeCheck: string(cinema.AgeCheck),
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateCinemaHandler godoc
+// @Summary Создать кинотеатр
+// @Tags admin-cinemas
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.CreateCinemaDTI true "Кинотеатр"
+// @Success 201 {object} dt.CreateCinemaDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas [post]
+func CreateCinemaHandler(c *gin.Context) {
+	var input dt.CreateCinemaDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dto, err := services.CreateCinema(input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTimezone) || errors.Is(err, services.ErrInvalidCinema) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto)
+}
+
+// UpdateCinemaHandler godoc
+// @Summary Обновить кинотеатр (частично)
+// @Tags admin-cinemas
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Param input body object true "Поля для обновления: name, location, phone, email, timezone, age_check (block или warn)"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas/{id} [patch]
+func UpdateCinemaHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+
+	var updates map[string]interface{}
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := services.UpdateCinema(uint(id), updates); err != nil {
+		switch {
+		case err.Error() == "кинотеатр не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case err.Error() == "пустой запрос", errors.Is(err, services.ErrInvalidTimezone), errors.Is(err, services.ErrInvalidCinema):
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "кинотеатр обновлён",
+	})
+}
+
+// DeleteCinemaHandler godoc
+// @Summary Удалить кинотеатр (только без действующих залов)
+// @Tags admin-cinemas
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas/{id} [delete]
+func DeleteCinemaHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+
+	if err := services.DeleteCinema(uint(id)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: "cinema not found",
+			})
+		case errors.Is(err, services.ErrInUse):
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "IN_USE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "кинотеатр удалён",
+	})
+}
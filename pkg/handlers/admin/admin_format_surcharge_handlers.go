@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFormatSurchargesHandler godoc
+// @Summary Получить наценки по форматам показа
+// @Tags admin-sessions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dt.FormatSurchargeDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/format-surcharges [get]
+func GetFormatSurchargesHandler(c *gin.Context) {
+	surcharges, err := services.GetFormatSurcharges()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, surcharges)
+}
+
+// SetFormatSurchargeHandler godoc
+// @Summary Задать наценку формата показа
+// @Description Наценка подставляется в новые сеансы формата и добавляется к цене любого места. Уже созданные сеансы не меняются
+// @Tags admin-sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param format path string true "Формат: 2d, 3d, imax, imax_3d"
+// @Param input body dt.FormatSurchargeDTI true "Наценка"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/format-surcharges/{format} [put]
+func SetFormatSurchargeHandler(c *gin.Context) {
+	var input dt.FormatSurchargeDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := services.SetFormatSurcharge(c.Param("format"), input.Amount); err != nil {
+		if errors.Is(err, services.ErrInvalidSession) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "наценка формата сохранена",
+	})
+}
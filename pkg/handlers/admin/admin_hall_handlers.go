@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCinemaHallsHandler godoc
+// @Summary Получить залы кинотеатра
+// @Tags admin-halls
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Success 200 {array} dt.HallDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas/{id}/halls [get]
+func GetCinemaHallsHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	cinemaID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+
+	halls, err := services.GetCinemaHalls(uint(cinemaID))
+	if err != nil {
+		writeHallError(c, err)
+		return
+	}
+
+	result := make([]dt.HallDTO, 0, len(halls))
+	for _, hall := range halls {
+		result = append(result, dt.HallDTO{
+			ID:            hall.ID,
+			CinemaID:      hall.CinemaID,
+			HallTypeID:    hall.HallTypeID,
+			HallType:      hall.HallType.Name,
+			Name:          hall.Name,
+			Capacity:      hall.Capacity,
+			Structure:     json.RawMessage(hall.Structure),
+			BufferMinutes: hall.BufferMinutes,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateHallHandler godoc
+// @Summary Создать зал в кинотеатре
+// @Tags admin-halls
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Param input body dt.CreateHallDTI true "Зал и его схема; capacity должна совпадать с числом мест в схеме"
+// @Success 201 {object} dt.CreateHallDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas/{id}/halls [post]
+func CreateHallHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	cinemaID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+
+	var input dt.CreateHallDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dto, err := services.CreateHall(uint(cinemaID), input)
+	if err != nil {
+		writeHallError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto)
+}
+
+// UpdateHallHandler godoc
+// @Summary Обновить зал (частично)
+// @Description При наличии предстоящих сеансов из схемы нельзя убирать места и закрывать места с бронями
+// @Tags admin-halls
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Param hall_id path int true "ID зала"
+// @Param input body dt.UpdateHallDTI true "Поля для обновления"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas/{id}/halls/{hall_id} [patch]
+func UpdateHallHandler(c *gin.Context) {
+	cinemaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+	hallID, err := strconv.ParseUint(c.Param("hall_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid hall ID",
+		})
+		return
+	}
+
+	var input dt.UpdateHallDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := services.UpdateHall(uint(cinemaID), uint(hallID), input); err != nil {
+		writeHallError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "зал обновлён",
+	})
+}
+
+// DeleteHallHandler godoc
+// @Summary Удалить зал (только без предстоящих сеансов)
+// @Tags admin-halls
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Param hall_id path int true "ID зала"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/cinemas/{id}/halls/{hall_id} [delete]
+func DeleteHallHandler(c *gin.Context) {
+	cinemaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+	hallID, err := strconv.ParseUint(c.Param("hall_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid hall ID",
+		})
+		return
+	}
+
+	if err := services.DeleteHall(uint(cinemaID), uint(hallID)); err != nil {
+		writeHallError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "зал удалён",
+	})
+}
+
+// UpdateHallStructureHandler godoc
+// @Summary Заменить схему зала (ряды, типы мест, координаты, закрытые места)
+// @Tags admin-halls
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID зала"
+// @Param input body services.HallStructure true "Схема зала"
+// @Success 200 {object} services.HallStructure
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/halls/{id}/structure [put]
+func UpdateHallStructureHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid hall ID",
+		})
+		return
+	}
+
+	var raw json.RawMessage
+	if err := c.ShouldBindJSON(&raw); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	structure, err := services.UpdateHallStructure(uint(id), raw)
+	if err != nil {
+		writeHallError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, structure)
+}
+
+// ошибки сервисов залов в HTTP-ответ
+func writeHallError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLayout):
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_LAYOUT",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrHallInUse):
+		c.JSON(http.StatusConflict, dt.ErrorResponse{
+			Code:    "HALL_IN_USE",
+			Message: err.Error(),
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "hall not found",
+		})
+	case err.Error() == "кинотеатр не найден", err.Error() == "тип зала не найден":
+		c.JSON(http.StatusNotFound, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: err.Error(),
+		})
+	case err.Error() == "пустой запрос", err.Error() == "название зала не может быть пустым":
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+}
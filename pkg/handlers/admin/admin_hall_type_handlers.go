@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetHallTypesHandler godoc
+// @Summary Получить все типы залов
+// @Tags admin-hall-types
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dt.HallTypeDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/hall-types [get]
+func GetHallTypesHandler(c *gin.Context) {
+	types, err := services.GetHallTypes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result := make([]dt.HallTypeDTO, 0, len(types))
+	for _, t := range types {
+		result = append(result, dt.HallTypeDTO{
+			ID:   t.ID,
+			Name: t.Name,
+			Desc: t.Desc,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateHallTypeHandler godoc
+// @Summary Создать тип зала
+// @Tags admin-hall-types
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.CreateHallTypeDTI true "Тип зала"
+// @Success 201 {object} dt.CreateHallTypeDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/hall-types [post]
+func CreateHallTypeHandler(c *gin.Context) {
+	var input dt.CreateHallTypeDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dto, err := services.CreateHallType(input)
+	if err != nil {
+		if err.Error() == "тип зала с таким названием уже существует" {
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "ALREADY_EXISTS",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto)
+}
+
+// UpdateHallTypeHandler godoc
+// @Summary Обновить тип зала (частично)
+// @Tags admin-hall-types
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID типа зала"
+// @Param input body object true "Поля для обновления: name, desc"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/hall-types/{id} [patch]
+func UpdateHallTypeHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid hall type ID",
+		})
+		return
+	}
+
+	var updates map[string]interface{}
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := services.UpdateHallType(uint(id), updates); err != nil {
+		switch err.Error() {
+		case "тип зала не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case "пустой запрос":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "тип зала обновлён",
+	})
+}
+
+// DeleteHallTypeHandler godoc
+// @Summary Удалить тип зала (только неиспользуемый)
+// @Tags admin-hall-types
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID типа зала"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/hall-types/{id} [delete]
+func DeleteHallTypeHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid hall type ID",
+		})
+		return
+	}
+
+	if err := services.DeleteHallType(uint(id)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: "hall type not found",
+			})
+		case errors.Is(err, services.ErrInUse):
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "IN_USE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "тип зала удалён",
+	})
+}
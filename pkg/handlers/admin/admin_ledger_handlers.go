@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckLedgerHandler godoc
+// @Summary Сверить журнал проводок
+// @Description Ищет несбалансированные проводки и расхождения балансов в профилях с суммой движений по счетам пользователей
+// @Tags admin-ledger
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dt.LedgerCheckDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/ledger/check [get]
+func CheckLedgerHandler(c *gin.Context) {
+	check, err := services.CheckLedger()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
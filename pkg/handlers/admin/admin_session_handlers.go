@@ -23,6 +23,8 @@ import (
 // @Failure 400 {object} dt.ErrorResponse
 // @Failure 401 {object} dt.ErrorResponse
 // @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ScheduleConflictResponse
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /admin/sessions [post]
 func CreateSessionHandler(c *gin.Context) {
@@ -37,10 +39,7 @@ func CreateSessionHandler(c *gin.Context) {
 
 	dto, err := services.CreateSession(input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
-			Code:    "INTERNAL_ERROR",
-			Message: err.Error(),
-		})
+		writeSessionError(c, err)
 		return
 	}
 
@@ -49,17 +48,19 @@ func CreateSessionHandler(c *gin.Context) {
 
 // UpdateSessionHandler godoc
 // @Summary Обновить сеанс (частично)
+// @Description Поля: film_id, hall_id, start_time, price, format, audio_language, subtitle_language, late_show, surcharge. При смене формата без surcharge подставляется наценка нового формата
 // @Tags admin-sessions
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "ID сеанса"
-// @Param input body object true "Поля для обновления"
+// @Param input body object true "Поля для обновления: film_id, hall_id, start_time, price"
 // @Success 200 {object} dt.ServAnswerDTO
 // @Failure 400 {object} dt.ErrorResponse
 // @Failure 401 {object} dt.ErrorResponse
 // @Failure 403 {object} dt.ErrorResponse
 // @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ScheduleConflictResponse
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /admin/sessions/{id} [patch]
 func UpdateSessionHandler(c *gin.Context) {
@@ -83,17 +84,7 @@ func UpdateSessionHandler(c *gin.Context) {
 	}
 
 	if err := services.UpdateSession(uint(id), updates); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, dt.ErrorResponse{
-				Code:    "NOT_FOUND",
-				Message: "session not found",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
-			Code:    "INTERNAL_ERROR",
-			Message: err.Error(),
-		})
+		writeSessionError(c, err)
 		return
 	}
 
@@ -103,12 +94,13 @@ func UpdateSessionHandler(c *gin.Context) {
 }
 
 // DeleteSessionHandler godoc
-// @Summary Удалить сеанс
+// @Summary Снять сеанс с расписания
+// @Description Сеанс не удаляется, а отменяется: оплаченные места возвращаются полностью, клиенты получают уведомление
 // @Tags admin-sessions
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "ID сеанса"
-// @Success 200 {object} dt.ServAnswerDTO
+// @Success 200 {object} dt.SessionCancelSummaryDTO
 // @Failure 400 {object} dt.ErrorResponse
 // @Failure 401 {object} dt.ErrorResponse
 // @Failure 403 {object} dt.ErrorResponse
@@ -116,6 +108,57 @@ func UpdateSessionHandler(c *gin.Context) {
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /admin/sessions/{id} [delete]
 func DeleteSessionHandler(c *gin.Context) {
+	cancelSession(c, "")
+}
+
+// CancelSessionHandler godoc
+// @Summary Отменить сеанс с возвратом всех билетов
+// @Description Оплаченные места возвращаются полностью (деньги и бонусы), удержания снимаются, каждому клиенту ставится уведомление. Возвращает сводку возвратов
+// @Tags admin-sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сеанса"
+// @Param input body dt.CancelSessionDTI true "Причина отмены"
+// @Success 200 {object} dt.SessionCancelSummaryDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/sessions/{id}/cancel [post]
+func CancelSessionHandler(c *gin.Context) {
+	var input dt.CancelSessionDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	cancelSession(c, input.Reason)
+}
+
+// SetSessionPricesHandler godoc
+// @Summary Задать цены категорий мест для сеанса
+// @Description Для каждой категории указывается либо price, либо multiplier к базовой цене. Категории без правила продаются по базовой цене
+// @Description multiplier — от 0 (не включая) до 100 с точностью до 0.0001, например 1.5
+// @Tags admin-sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сеанса"
+// @Param input body []dt.SessionPriceDTI true "Цены по категориям"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/sessions/{id}/prices [put]
+func SetSessionPricesHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -126,22 +169,180 @@ func DeleteSessionHandler(c *gin.Context) {
 		return
 	}
 
-	if err := services.DeleteSession(uint(id)); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	var input []dt.SessionPriceDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := services.SetSessionPrices(uint(id), input); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPrice):
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_PRICE",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrSessionStarted):
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_STARTED",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrSessionCanceled):
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "SESSION_CANCELED",
+				Message: err.Error(),
+			})
+		case errors.Is(err, gorm.ErrRecordNotFound):
 			c.JSON(http.StatusNotFound, dt.ErrorResponse{
 				Code:    "NOT_FOUND",
 				Message: "session not found",
 			})
-			return
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
 		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "цены сеанса обновлены",
+	})
+}
+
+// ValidateSessionHandler godoc
+// @Summary Проверить сеанс на пересечения в зале без сохранения
+// @Description Учитывает длительность фильма и перерыв зала. Для переноса существующего сеанса передайте session_id
+// @Tags admin-sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.ValidateSessionDTI true "Фильм, зал и время начала"
+// @Success 200 {object} dt.ScheduleCheckDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/sessions/validate [post]
+func ValidateSessionHandler(c *gin.Context) {
+	var input dt.ValidateSessionDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := services.ValidateSessionSchedule(input)
+	if err != nil {
+		writeSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ошибки сервисов сеансов в HTTP-ответ
+func writeSessionError(c *gin.Context, err error) {
+	var conflict *services.ScheduleConflictError
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, dt.ScheduleConflictResponse{
+			Code:        "SCHEDULE_CONFLICT",
+			Message:     err.Error(),
+			ConflictIDs: conflict.SessionIDs,
+		})
+	case errors.Is(err, services.ErrInvalidSession):
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidPrice):
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_PRICE",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrSessionCanceled):
+		c.JSON(http.StatusConflict, dt.ErrorResponse{
+			Code:    "SESSION_CANCELED",
+			Message: err.Error(),
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "session not found",
+		})
+	case err.Error() == "фильм не найден", err.Error() == "зал не найден":
+		c.JSON(http.StatusNotFound, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: err.Error(),
+		})
+	case err.Error() == "пустой запрос":
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+	default:
 		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: err.Error(),
 		})
+	}
+}
+
+// общая часть отмены сеанса для DELETE и POST /cancel
+func cancelSession(c *gin.Context, reason string) {
+	adminID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
 		return
 	}
 
-	c.JSON(http.StatusOK, dt.ServAnswerDTO{
-		Answer: "сеанс удалён",
-	})
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid session ID",
+		})
+		return
+	}
+
+	summary, err := services.CancelSession(uint(id), adminID.(uint), reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: "session not found",
+			})
+		case errors.Is(err, services.ErrSessionCanceled):
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "SESSION_CANCELED",
+				Message: err.Error(),
+			})
+		case err.Error() == "сеанс уже завершён":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_STATE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
 }
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PreviewSessionSeriesHandler godoc
+// @Summary Предпросмотр серии сеансов по шаблону
+// @Description Показывает, какие сеансы будут созданы и с какими существующими сеансами зала они пересекаются. Ничего не сохраняет
+// @Tags admin-session-series
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.CreateSessionSeriesDTI true "Шаблон расписания"
+// @Success 200 {object} dt.SessionSeriesPreviewDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/session-series/preview [post]
+func PreviewSessionSeriesHandler(c *gin.Context) {
+	var input dt.CreateSessionSeriesDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	preview, err := services.PreviewSessionSeries(input)
+	if err != nil {
+		writeSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// CreateSessionSeriesHandler godoc
+// @Summary Создать серию сеансов по шаблону
+// @Description Все сеансы создаются одной транзакцией; при любом пересечении не создаётся ничего
+// @Tags admin-session-series
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.CreateSessionSeriesDTI true "Шаблон расписания"
+// @Success 201 {object} dt.CreateSessionSeriesDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ScheduleConflictResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/session-series [post]
+func CreateSessionSeriesHandler(c *gin.Context) {
+	var input dt.CreateSessionSeriesDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dto, err := services.CreateSessionSeries(input)
+	if err != nil {
+		writeSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto)
+}
+
+// GetSessionSeriesListHandler godoc
+// @Summary Получить все серии сеансов
+// @Tags admin-session-series
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dt.SessionSeriesDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/session-series [get]
+func GetSessionSeriesListHandler(c *gin.Context) {
+	series, err := services.GetSessionSeriesList()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// GetSessionSeriesHandler godoc
+// @Summary Получить серию с её сеансами
+// @Tags admin-session-series
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID серии"
+// @Success 200 {object} dt.SessionSeriesDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/session-series/{id} [get]
+func GetSessionSeriesHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid series ID",
+		})
+		return
+	}
+
+	series, err := services.GetSessionSeries(uint(id))
+	if err != nil {
+		writeSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// UpdateSessionSeriesHandler godoc
+// @Summary Изменить ещё не начавшиеся сеансы серии
+// @Tags admin-session-series
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID серии"
+// @Param input body dt.UpdateSessionSeriesDTI true "Фильм, базовая цена, цены категорий"
+// @Success 200 {object} dt.SeriesUpdateResultDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ScheduleConflictResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/session-series/{id} [patch]
+func UpdateSessionSeriesHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid series ID",
+		})
+		return
+	}
+
+	var input dt.UpdateSessionSeriesDTI
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := services.UpdateSessionSeries(uint(id), input)
+	if err != nil {
+		writeSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteSessionSeriesHandler godoc
+// @Summary Удалить ещё не начавшиеся сеансы серии
+// @Description Сеансы с действующими бронями не удаляются и возвращаются в kept_ids
+// @Tags admin-session-series
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID серии"
+// @Success 200 {object} dt.SeriesDeleteResultDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /admin/session-series/{id} [delete]
+func DeleteSessionSeriesHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid series ID",
+		})
+		return
+	}
+
+	result, err := services.DeleteSessionSeries(uint(id))
+	if err != nil {
+		writeSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// то же, что writeSessionError, но «не найдено» относится к серии
+func writeSeriesError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "series not found",
+		})
+		return
+	}
+	writeSessionError(c, err)
+}
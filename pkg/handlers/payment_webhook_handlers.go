@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/payments"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentWebhookHandler godoc
+// @Summary Уведомление платёжного шлюза о смене статуса платежа
+// @Description Тело подписывается HMAC-SHA256 общим секретом, подпись в заголовке X-Payment-Signature (t=<unix>,v1=<hex>).
+// @Description Статус платежа запрашивается у шлюза, из события берутся только ID события и платежа.
+// @Description Повтор события с тем же ID возвращает duplicate и ничего не меняет; завершённый платёж запоздавшими событиями не меняется
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Подпись уведомления"
+// @Param input body payments.Event true "Событие платежа"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Failure 502 {object} dt.ErrorResponse
+// @Failure 503 {object} dt.ErrorResponse
+// @Router /payments/webhook [post]
+func PaymentWebhookHandler(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	duplicate, err := services.HandlePaymentWebhook(body, c.GetHeader(payments.SignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, payments.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+				Code:    "INVALID_SIGNATURE",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrWebhookDisabled):
+			c.JSON(http.StatusServiceUnavailable, dt.ErrorResponse{
+				Code:    "WEBHOOK_DISABLED",
+				Message: err.Error(),
+			})
+		case err.Error() == "некорректное уведомление",
+			err.Error() == "в уведомлении нет ID события или платежа":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+		case err.Error() == "платёж не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrPaymentGateway):
+			c.JSON(http.StatusBadGateway, dt.ErrorResponse{
+				Code:    "PAYMENT_GATEWAY_ERROR",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	answer := "ok"
+	if duplicate {
+		answer = "duplicate"
+	}
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: answer,
+	})
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/payments"
 	"CinemaBooking/pkg/services"
 
 	"github.com/gin-gonic/gin"
@@ -11,14 +14,21 @@ import (
 
 // CreateBookingHandler godoc
 // @Summary Забронировать билет
+// @Description payment_method=wallet (по умолчанию) — оплата с баланса сразу (201).
+// @Description payment_method=card — место удерживается, создаётся платёж в шлюзе (202); бронь оплачивается после его подтверждения
 // @Tags bookings
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param input body dt.CreateBookingDTI true "Данные для бронирования"
 // @Success 201 {object} dt.CreateBookingDTO
+// @Success 202 {object} dt.CreateBookingDTO
 // @Failure 400 {object} dt.ErrorResponse
 // @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 402 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 502 {object} dt.ErrorResponse
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /bookings [post]
 func CreateBookingHandler(c *gin.Context) {
@@ -45,6 +55,62 @@ func CreateBookingHandler(c *gin.Context) {
 	booking, err := services.CreateBooking(input)
 
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidSeat) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_SEAT",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionStarted) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_STARTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionCanceled) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_CANCELED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAgeRestricted) {
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "AGE_RESTRICTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInsufficientFunds) || errors.Is(err, services.ErrInsufficientBonus) {
+			c.JSON(http.StatusPaymentRequired, dt.ErrorResponse{
+				Code:    "INSUFFICIENT_FUNDS",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSeatTaken) {
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "SEAT_TAKEN",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrPaymentGateway) {
+			c.JSON(http.StatusBadGateway, dt.ErrorResponse{
+				Code:    "PAYMENT_GATEWAY_ERROR",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, payments.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: err.Error(),
@@ -53,8 +119,564 @@ func CreateBookingHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dt.CreateBookingDTO{
-		ID:     booking.ID,
-		Status: booking.Status,
+	result := dt.CreateBookingDTO{
+		ID:        booking.ID,
+		Status:    booking.Status,
+		ExpiresAt: booking.ExpiresAt,
+		Warning:   booking.AgeWarning,
+	}
+	if booking.Payment != nil {
+		result.Payment = &dt.BookingPaymentDTO{
+			ID:        booking.Payment.ID,
+			BookingID: booking.ID,
+			Amount:    booking.Payment.Amount,
+			Currency:  booking.Payment.Currency,
+			Status:    string(booking.Payment.Status),
+			CreatedAt: booking.Payment.CreatedAt,
+		}
+		c.JSON(http.StatusAccepted, result)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// HoldSeatHandler godoc
+// @Summary Временно удержать место до оплаты
+// @Tags bookings
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.HoldSeatDTI true "Сеанс и место"
+// @Success 201 {object} dt.HoldSeatDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /bookings/hold [post]
+func HoldSeatHandler(c *gin.Context) {
+	var input dt.HoldSeatDTI
+
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "User not found",
+		})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	input.UserID = userID.(uint)
+	booking, err := services.HoldSeat(input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSeat) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_SEAT",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionStarted) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_STARTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionCanceled) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_CANCELED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAgeRestricted) {
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "AGE_RESTRICTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		switch {
+		case err.Error() == "сеанс не найден", err.Error() == "пользователь не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrSeatTaken):
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "SEAT_TAKEN",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dt.HoldSeatDTO{
+		ID:        booking.ID,
+		Status:    booking.Status,
+		ExpiresAt: *booking.ExpiresAt,
+		Warning:   booking.AgeWarning,
 	})
 }
+
+// ConfirmBookingHandler godoc
+// @Summary Оплатить удержанное место
+// @Tags bookings
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID бронирования"
+// @Param input body dt.ConfirmBookingDTI false "Параметры оплаты"
+// @Success 200 {object} dt.CreateBookingDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 402 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 410 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /bookings/{id}/confirm [post]
+func ConfirmBookingHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "User not found",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	bookingID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid booking ID",
+		})
+		return
+	}
+
+	var input dt.ConfirmBookingDTI
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	booking, err := services.ConfirmBooking(uint(bookingID), userID.(uint), input.UseBonus)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSeat) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_SEAT",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionStarted) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_STARTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionCanceled) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_CANCELED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAgeRestricted) {
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "AGE_RESTRICTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInsufficientFunds) || errors.Is(err, services.ErrInsufficientBonus) {
+			c.JSON(http.StatusPaymentRequired, dt.ErrorResponse{
+				Code:    "INSUFFICIENT_FUNDS",
+				Message: err.Error(),
+			})
+			return
+		}
+		switch err.Error() {
+		case "бронирование не найдено":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case "нельзя подтвердить чужое бронирование":
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: err.Error(),
+			})
+		case "бронирование нельзя подтвердить", "бронирование ожидает оплаты картой":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_STATE",
+				Message: err.Error(),
+			})
+		case "время удержания места истекло":
+			c.JSON(http.StatusGone, dt.ErrorResponse{
+				Code:    "HOLD_EXPIRED",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.CreateBookingDTO{
+		ID:      booking.ID,
+		Status:  booking.Status,
+		Warning: booking.AgeWarning,
+	})
+}
+
+// GetMyBookingsHandler godoc
+// @Summary Получить историю своих бронирований
+// @Tags bookings
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "upcoming / past / cancelled"
+// @Param page query int false "Номер страницы (с 1)"
+// @Param limit query int false "Размер страницы (до 100)"
+// @Success 200 {object} dt.BookingListDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /bookings [get]
+func GetMyBookingsHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
+		return
+	}
+
+	var filter dt.BookingFilterDTI
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	bookings, err := services.GetMyBookings(userID.(uint), filter)
+	if err != nil {
+		if err.Error() == "неизвестный фильтр статуса" {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, bookings)
+}
+
+// GetMyBookingHandler godoc
+// @Summary Получить билет (подробности бронирования)
+// @Tags bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID бронирования"
+// @Success 200 {object} dt.BookingDetailDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Router /bookings/{id} [get]
+func GetMyBookingHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	bookingID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid booking ID",
+		})
+		return
+	}
+
+	booking, err := services.GetMyBooking(uint(bookingID), userID.(uint))
+	if err != nil {
+		switch err.Error() {
+		case "бронирование не найдено":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// CancelMyBookingHandler godoc
+// @Summary Отменить своё бронирование
+// @Tags bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID бронирования"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 402 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /bookings/{id} [delete]
+func CancelMyBookingHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	bookingID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid booking ID",
+		})
+		return
+	}
+
+	err = services.CancelBooking(uint(bookingID), userID.(uint))
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientFunds) || errors.Is(err, services.ErrInsufficientBonus) {
+			c.JSON(http.StatusPaymentRequired, dt.ErrorResponse{
+				Code:    "INSUFFICIENT_FUNDS",
+				Message: err.Error(),
+			})
+			return
+		}
+		switch err.Error() {
+		case "бронирование не найдено":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case "нельзя отменить чужое бронирование":
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: err.Error(),
+			})
+		case "бронирование нельзя отменить", "место входит в заказ, отмените заказ целиком", "сеанс уже начался, отмена невозможна":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_STATE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "бронирование отменено",
+	})
+}
+
+// PreviewBookingRefundHandler godoc
+// @Summary Узнать сумму возврата при отмене бронирования
+// @Tags bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID бронирования"
+// @Success 200 {object} dt.RefundPreviewDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /bookings/{id}/refund-preview [get]
+func PreviewBookingRefundHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	bookingID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid booking ID",
+		})
+		return
+	}
+
+	preview, err := services.PreviewBookingRefund(uint(bookingID), userID.(uint))
+	if err != nil {
+		switch err.Error() {
+		case "бронирование не найдено":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case "нельзя просматривать чужое бронирование":
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: err.Error(),
+			})
+		case "бронирование нельзя отменить", "сеанс уже начался, отмена невозможна":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_STATE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// ConfirmBookingPaymentHandler godoc
+// @Summary Подтвердить оплату брони картой (оплатить)
+// @Description Передаёт подтверждение в шлюз. Если шлюз ответит позже, место остаётся удержанным до уведомления.
+// @Description При отказе бронь снимается и место освобождается
+// @Tags bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID бронирования"
+// @Success 200 {object} dt.BookingPaymentDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 502 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /bookings/{id}/payment/confirm [post]
+func ConfirmBookingPaymentHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user not found in context",
+		})
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid booking ID",
+		})
+		return
+	}
+
+	payment, err := services.ConfirmBookingPayment(uint(bookingID), userID.(uint))
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// GetBookingPaymentHandler godoc
+// @Summary Получить оплату брони картой и её статус
+// @Tags bookings
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID бронирования"
+// @Success 200 {object} dt.BookingPaymentDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /bookings/{id}/payment [get]
+func GetBookingPaymentHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user not found in context",
+		})
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid booking ID",
+		})
+		return
+	}
+
+	payment, err := services.GetBookingPayment(uint(bookingID), userID.(uint))
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
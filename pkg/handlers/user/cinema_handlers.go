@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllCinemasHandler godoc
+// @Summary Получить список кинотеатров
+// @Tags cinemas
+// @Produce json
+// @Success 200 {array} dt.CinemaDTO
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /cinemas [get]
+func GetAllCinemasHandler(c *gin.Context) {
+	cinemas, err := services.GetCinemas()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result := make([]dt.CinemaDTO, 0, len(cinemas))
+	for _, cinema := range cinemas {
+		result = append(result, dt.CinemaDTO{
+			ID:       cinema.ID,
+			Name:     cinema.Name,
+			Location: cinema.Location,
+			Phone:    cinema.Phone,
+			Email:    cinema.Email,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetCinemaHandler godoc
+// @Summary Получить кинотеатр с его залами
+// @Tags cinemas
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Success 200 {object} dt.CinemaDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /cinemas/{id} [get]
+func GetCinemaHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+
+	cinema, err := services.GetCinema(uint(id))
+	if err != nil {
+		if err.Error() == "кинотеатр не найден" {
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, cinema)
+}
+
+// GetCinemaScheduleHandler godoc
+// @Summary Расписание кинотеатра на день, сгруппированное по фильмам и залам
+// @Tags cinemas
+// @Produce json
+// @Param id path int true "ID кинотеатра"
+// @Param date query string false "Дата YYYY-MM-DD, по умолчанию сегодня"
+// @Success 200 {object} dt.CinemaScheduleDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /cinemas/{id}/sessions [get]
+func GetCinemaScheduleHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid cinema ID",
+		})
+		return
+	}
+
+	schedule, err := services.GetCinemaSchedule(uint(id), c.Query("date"))
+	if err != nil {
+		switch err.Error() {
+		case "кинотеатр не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case "неверный формат даты, ожидается YYYY-MM-DD":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
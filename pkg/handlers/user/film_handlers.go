@@ -72,8 +72,10 @@ func GetFilmHandler(c *gin.Context) {
 // @Tags films
 // @Produce json
 // @Param genres query string false "Список ID жанров через запятую"
+// @Param allowed_only query bool false "Только фильмы, доступные текущему пользователю по возрасту (нужен токен)"
 // @Success 200 {array} dt.FilmDTO
 // @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /films [get]
 func GetAllFilmsHandler(c *gin.Context) {
@@ -95,7 +97,18 @@ func GetAllFilmsHandler(c *gin.Context) {
 		}
 	}
 
-	films, err := services.GetAllFilms(genreIDs)
+	var viewerID uint
+	if c.Query("allowed_only") == "true" {
+		if viewerID = c.GetUint("user_id"); viewerID == 0 {
+			c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: "User not found",
+			})
+			return
+		}
+	}
+
+	films, err := services.GetAllFilms(genreIDs, viewerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyNotificationsHandler godoc
+// @Summary Получить свои уведомления
+// @Description Например, об отмене сеанса и возврате средств. Новые сверху
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dt.NotificationDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /notifications [get]
+func GetMyNotificationsHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user not found in context",
+		})
+		return
+	}
+
+	notifications, err := services.GetMyNotifications(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
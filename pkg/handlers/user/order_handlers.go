@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateOrderHandler godoc
+// @Summary Купить несколько мест одним заказом
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body dt.CreateOrderDTI true "Сеанс и список мест"
+// @Success 201 {object} dt.OrderDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 402 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /orders [post]
+func CreateOrderHandler(c *gin.Context) {
+	var input dt.CreateOrderDTI
+
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "User not found",
+		})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	input.UserID = userID.(uint)
+	order, err := services.CreateOrder(input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSeat) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_SEAT",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionStarted) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_STARTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSessionCanceled) {
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "SESSION_CANCELED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAgeRestricted) {
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "AGE_RESTRICTED",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInsufficientFunds) || errors.Is(err, services.ErrInsufficientBonus) {
+			c.JSON(http.StatusPaymentRequired, dt.ErrorResponse{
+				Code:    "INSUFFICIENT_FUNDS",
+				Message: err.Error(),
+			})
+			return
+		}
+		switch {
+		case err.Error() == "сеанс не найден", err.Error() == "пользователь не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrSeatTaken):
+			c.JSON(http.StatusConflict, dt.ErrorResponse{
+				Code:    "SEAT_TAKEN",
+				Message: err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "место указано дважды"),
+			err.Error() == "не выбрано ни одного места":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_INPUT",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeOrderDTO(order))
+}
+
+// GetOrderHandler godoc
+// @Summary Получить заказ
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 200 {object} dt.OrderDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Router /orders/{id} [get]
+func GetOrderHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	orderID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid order ID",
+		})
+		return
+	}
+
+	order, err := services.GetOrder(uint(orderID), userID.(uint))
+	if err != nil {
+		switch err.Error() {
+		case "заказ не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, makeOrderDTO(order))
+}
+
+// CancelOrderHandler godoc
+// @Summary Отменить заказ целиком
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 200 {object} dt.ServAnswerDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 402 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /orders/{id} [delete]
+func CancelOrderHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	orderID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid order ID",
+		})
+		return
+	}
+
+	if err := services.CancelOrder(uint(orderID), userID.(uint)); err != nil {
+		if errors.Is(err, services.ErrInsufficientFunds) || errors.Is(err, services.ErrInsufficientBonus) {
+			c.JSON(http.StatusPaymentRequired, dt.ErrorResponse{
+				Code:    "INSUFFICIENT_FUNDS",
+				Message: err.Error(),
+			})
+			return
+		}
+		switch err.Error() {
+		case "заказ не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case "нельзя отменить чужой заказ":
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: err.Error(),
+			})
+		case "заказ нельзя отменить", "сеанс уже начался, отмена невозможна":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_STATE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dt.ServAnswerDTO{
+		Answer: "заказ отменён",
+	})
+}
+
+// PreviewOrderRefundHandler godoc
+// @Summary Узнать сумму возврата при отмене заказа
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 200 {object} dt.RefundPreviewDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /orders/{id}/refund-preview [get]
+func PreviewOrderRefundHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user_id not found",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	orderID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid order ID",
+		})
+		return
+	}
+
+	preview, err := services.PreviewOrderRefund(uint(orderID), userID.(uint))
+	if err != nil {
+		switch err.Error() {
+		case "заказ не найден":
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case "нельзя просматривать чужой заказ":
+			c.JSON(http.StatusForbidden, dt.ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: err.Error(),
+			})
+		case "заказ нельзя отменить", "сеанс уже начался, отмена невозможна":
+			c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+				Code:    "INVALID_STATE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// собирает ответ по заказу
+func makeOrderDTO(order *models.Order) dt.OrderDTO {
+	result := dt.OrderDTO{
+		ID:            order.ID,
+		SessionID:     order.SessionID,
+		Status:        order.Status,
+		SpendBonus:    order.SpendBonus,
+		ReceivedBonus: order.ReceivedBonus,
+		TotalPrice:    order.TotalPrice,
+		Warning:       order.AgeWarning,
+	}
+	for _, b := range order.Bookings {
+		result.Bookings = append(result.Bookings, dt.OrderBookingDTO{
+			ID:      b.ID,
+			RowNum:  b.RowNum,
+			SeatNum: b.SeatNum,
+		})
+	}
+
+	return result
+}
@@ -1,16 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/money"
+	"CinemaBooking/pkg/payments"
 	"CinemaBooking/pkg/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetMyPaymentsHandler godoc
-// @Summary Получить историю пополнений
+// @Summary Получить историю операций по балансу
 // @Tags wallet
 // @Security BearerAuth
 // @Produce json
@@ -37,28 +41,21 @@ func GetMyPaymentsHandler(c *gin.Context) {
 		return
 	}
 
-	var result []dt.PaymentHistoryDTO
-	for _, p := range payments {
-		result = append(result, dt.PaymentHistoryDTO{
-			Amount:    p.Amount,
-			Operation: string(p.Operation),
-			CreatedAt: p.CreatedAt,
-		})
-	}
-
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, payments)
 }
 
 // RefillMyBalanceHandler godoc
-// @Summary Пополнить баланс
+// @Summary Начать пополнение баланса
+// @Description Создаёт платёж в шлюзе в статусе pending. Баланс пополняется только после подтверждения шлюзом
 // @Tags wallet
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param input body dt.RefillBalanceDTI true "Сумма пополнения"
-// @Success 200 {object} dt.ServAnswerDTO
+// @Success 202 {object} dt.RefillDTO
 // @Failure 400 {object} dt.ErrorResponse
 // @Failure 401 {object} dt.ErrorResponse
+// @Failure 502 {object} dt.ErrorResponse
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /wallet/refill [post]
 func RefillMyBalanceHandler(c *gin.Context) {
@@ -77,19 +74,132 @@ func RefillMyBalanceHandler(c *gin.Context) {
 			Code:    "INVALID_INPUT",
 			Message: err.Error(),
 		})
+		return
+	}
+
+	refill, err := services.RefillMyBalance(userID.(uint), input.Amount, input.Currency)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, refill)
+}
+
+// ConfirmRefillHandler godoc
+// @Summary Подтвердить пополнение (оплатить)
+// @Description Передаёт подтверждение в шлюз. Если шлюз ответит позже, пополнение останется в статусе pending до уведомления
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID пополнения"
+// @Success 200 {object} dt.RefillDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 409 {object} dt.ErrorResponse
+// @Failure 502 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /wallet/refills/{id}/confirm [post]
+func ConfirmRefillHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user not found in context",
+		})
+		return
 	}
 
-	if err := services.RefillMyBalance(userID.(uint), input.Amount); err != nil {
+	refillID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid refill ID",
+		})
+		return
+	}
+
+	refill, err := services.ConfirmRefill(uint(refillID), userID.(uint))
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, refill)
+}
+
+// GetMyRefillsHandler godoc
+// @Summary Получить свои пополнения через платёжный шлюз
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dt.RefillDTO
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /wallet/refills [get]
+func GetMyRefillsHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user not found in context",
+		})
+		return
+	}
+
+	refills, err := services.GetMyRefills(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
 			Code:    "INTERNAL_ERROR",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, dt.ServAnswerDTO{
-		Answer: "баланс успешно пополнен",
-	})
+	c.JSON(http.StatusOK, refills)
+}
+
+// GetMyRefillHandler godoc
+// @Summary Получить пополнение и его статус
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "ID пополнения"
+// @Success 200 {object} dt.RefillDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 403 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /wallet/refills/{id} [get]
+func GetMyRefillHandler(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "user not found in context",
+		})
+		return
+	}
+
+	refillID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid refill ID",
+		})
+		return
+	}
+
+	refill, err := services.GetMyRefill(uint(refillID), userID.(uint))
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, refill)
 }
 
 // GetBalanceHandler godoc
@@ -120,5 +230,42 @@ func GetBalanceHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dt.PaymentDTO{Balance: balance})
+	c.JSON(http.StatusOK, dt.PaymentDTO{Balance: balance, Currency: money.Default})
+}
+
+// ошибки платежей через платёжный шлюз: пополнений и оплаты брони картой
+func writePaymentError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "платёж не найден", err.Error() == "бронирование не найдено":
+		c.JSON(http.StatusNotFound, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: err.Error(),
+		})
+	case err.Error() == "нельзя просматривать чужой платёж", err.Error() == "нельзя просматривать чужое бронирование":
+		c.JSON(http.StatusForbidden, dt.ErrorResponse{
+			Code:    "FORBIDDEN",
+			Message: err.Error(),
+		})
+	case err.Error() == "сумма пополнения должна быть больше нуля", errors.Is(err, money.ErrInvalidCurrency),
+		errors.Is(err, payments.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+	case errors.Is(err, payments.ErrInvalidState):
+		c.JSON(http.StatusConflict, dt.ErrorResponse{
+			Code:    "INVALID_STATE",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrPaymentGateway):
+		c.JSON(http.StatusBadGateway, dt.ErrorResponse{
+			Code:    "PAYMENT_GATEWAY_ERROR",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/money"
 	"CinemaBooking/pkg/services"
 
 	"github.com/gin-gonic/gin"
@@ -43,6 +44,7 @@ func GetUserInfoHandler(c *gin.Context) {
 		Email:      profile.Email,
 		Balance:    profile.Balance,
 		Bonus:      profile.Bonus,
+		Currency:   money.Default,
 	})
 }
 
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -12,33 +13,39 @@ import (
 
 // GetAllSessionsHandler godoc
 // @Summary Получить все предстоящие сеансы
+// @Description Фильтры по параметрам показа: формат, язык звука, субтитры (код языка или none), поздний сеанс 18+
 // @Tags sessions
 // @Produce json
+// @Param format query string false "Формат: 2d, 3d, imax, imax_3d"
+// @Param audio query string false "Язык звука, например ru или en"
+// @Param subtitles query string false "Язык субтитров или none — без субтитров"
+// @Param late_show query bool false "Только поздние сеансы 18+ (true) или только обычные (false)"
+// @Param allowed_only query bool false "Только сеансы, доступные текущему пользователю по возрасту (нужен токен)"
 // @Success 200 {array} dt.SessionDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /sessions [get]
 func GetAllSessionsHandler(c *gin.Context) {
-	sessions, err := services.GetAllSessions()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
-			Code:    "INTERNAL_ERROR",
+	var filter dt.SessionFilterDTI
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
 			Message: err.Error(),
 		})
 		return
 	}
+	if !bindViewer(c, &filter) {
+		return
+	}
 
-	var result []dt.SessionDTO
-	for _, s := range sessions {
-		result = append(result, dt.SessionDTO{
-			ID:        s.ID,
-			FilmID:    s.FilmID,
-			HallID:    s.HallID,
-			StartTime: s.StartTime,
-			Price:     s.Price,
-		})
+	sessions, err := services.GetAllSessions(filter)
+	if err != nil {
+		writeSessionListError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, sessions)
 }
 
 // GetSessionsByFilmHandler godoc
@@ -46,8 +53,14 @@ func GetAllSessionsHandler(c *gin.Context) {
 // @Tags sessions
 // @Produce json
 // @Param id path int true "ID фильма"
+// @Param format query string false "Формат: 2d, 3d, imax, imax_3d"
+// @Param audio query string false "Язык звука, например ru или en"
+// @Param subtitles query string false "Язык субтитров или none — без субтитров"
+// @Param late_show query bool false "Только поздние сеансы 18+ (true) или только обычные (false)"
+// @Param allowed_only query bool false "Только сеансы, доступные текущему пользователю по возрасту (нужен токен)"
 // @Success 200 {array} dt.SessionDTO
 // @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
 // @Failure 500 {object} dt.ErrorResponse
 // @Router /sessions/film/{id} [get]
 func GetSessionsByFilmHandler(c *gin.Context) {
@@ -61,31 +74,82 @@ func GetSessionsByFilmHandler(c *gin.Context) {
 		return
 	}
 
-	sessions, err := services.GetSessionsByFilm(uint(filmID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
-			Code:    "INTERNAL_ERROR",
+	var filter dt.SessionFilterDTI
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
 			Message: err.Error(),
 		})
 		return
 	}
+	if !bindViewer(c, &filter) {
+		return
+	}
 
-	var result []dt.SessionDTO
-	for _, s := range sessions {
-		result = append(result, dt.SessionDTO{
-			ID:        s.ID,
-			FilmID:    s.FilmID,
-			HallID:    s.HallID,
-			StartTime: s.StartTime,
-			Price:     s.Price,
+	sessions, err := services.GetSessionsByFilm(uint(filmID), filter)
+	if err != nil {
+		writeSessionListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// SearchSessionsHandler godoc
+// @Summary Поиск сеансов
+// @Description Даты YYYY-MM-DD включительно (по умолчанию от сегодня на 2 месяца), окно времени HH:MM может переходить через полночь. Жанры — ID через запятую, у фильма должны быть все. Цена — обычного места с наценкой за формат
+// @Tags sessions
+// @Produce json
+// @Param date_from query string false "Дата с (YYYY-MM-DD)"
+// @Param date_to query string false "Дата по (YYYY-MM-DD), включительно"
+// @Param time_from query string false "Начало не раньше (HH:MM)"
+// @Param time_to query string false "Начало раньше (HH:MM)"
+// @Param cinema_id query int false "ID кинотеатра"
+// @Param hall_type_id query int false "ID типа зала"
+// @Param film_id query int false "ID фильма"
+// @Param genres query string false "Список ID жанров через запятую"
+// @Param max_age query int false "Максимальный возрастной рейтинг"
+// @Param price_min query number false "Цена от"
+// @Param price_max query number false "Цена до"
+// @Param adjacent_seats query int false "Нужно свободных мест подряд"
+// @Param format query string false "Формат: 2d, 3d, imax, imax_3d"
+// @Param audio query string false "Язык звука"
+// @Param subtitles query string false "Язык субтитров или none"
+// @Param late_show query bool false "Поздний сеанс 18+"
+// @Param allowed_only query bool false "Только сеансы, доступные текущему пользователю по возрасту (нужен токен)"
+// @Param sort query string false "start_time, price или title"
+// @Param order query string false "asc или desc"
+// @Param page query int false "Номер страницы"
+// @Param limit query int false "Размер страницы (до 100)"
+// @Success 200 {object} dt.SessionSearchResultDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 401 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /sessions/search [get]
+func SearchSessionsHandler(c *gin.Context) {
+	var filter dt.SessionSearchDTI
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
 		})
+		return
+	}
+	if !bindViewer(c, &filter.SessionFilterDTI) {
+		return
+	}
+
+	result, err := services.SearchSessions(filter)
+	if err != nil {
+		writeSessionListError(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
 // GetSeatsBySessionHandler godoc
-// @Summary Получить все места на сеанс с их статусом (free/taken)
+// @Summary Получить карту мест сеанса: тип, подпись, координаты, цена и статус (free/taken/blocked)
 // @Tags sessions
 // @Produce json
 // @Param id path int true "ID сеанса"
@@ -124,3 +188,71 @@ func GetAvailableSeatsHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, seats)
 }
+
+// GetSessionPricesHandler godoc
+// @Summary Получить цены сеанса по категориям мест
+// @Tags sessions
+// @Produce json
+// @Param id path int true "ID сеанса"
+// @Success 200 {array} dt.SessionPriceDTO
+// @Failure 400 {object} dt.ErrorResponse
+// @Failure 404 {object} dt.ErrorResponse
+// @Failure 500 {object} dt.ErrorResponse
+// @Router /sessions/{id}/prices [get]
+func GetSessionPricesHandler(c *gin.Context) {
+	sessionIDStr := c.Param("id")
+	sessionID, err := strconv.ParseUint(sessionIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: "invalid session ID",
+		})
+		return
+	}
+
+	prices, err := services.GetSessionPrices(uint(sessionID))
+	if err != nil {
+		if err.Error() == "сеанс не найден" {
+			c.JSON(http.StatusNotFound, dt.ErrorResponse{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+func writeSessionListError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidSession) {
+		c.JSON(http.StatusBadRequest, dt.ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dt.ErrorResponse{
+		Code:    "INTERNAL_ERROR",
+		Message: err.Error(),
+	})
+}
+
+// пользователь для фильтра allowed_only: токен необязателен, но без него фильтр недоступен
+func bindViewer(c *gin.Context, filter *dt.SessionFilterDTI) bool {
+	filter.ViewerID = c.GetUint("user_id")
+	if filter.AllowedOnly && filter.ViewerID == 0 {
+		c.JSON(http.StatusUnauthorized, dt.ErrorResponse{
+			Code:    "NOT_FOUND",
+			Message: "User not found",
+		})
+		return false
+	}
+	return true
+}
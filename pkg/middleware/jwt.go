@@ -15,47 +15,66 @@ import (
 
 func AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
-
-		header := c.GetHeader("Authorization")
-		if !strings.HasPrefix(header, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Отсутствует токен"})
+		userID, userType, msg := authenticate(c.GetHeader("Authorization"))
+		if msg != "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": msg})
 			return
 		}
 
-		tokenStr := strings.TrimPrefix(header, "Bearer ")
+		c.Set("user_id", userID)
+		c.Set("user_type", userType)
+		c.Next()
+	}
+}
 
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.GetJWTSecret()), nil
-		})
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Невалидный токен"})
-			return
+// Авторизация по желанию: с валидным токеном пользователь известен обработчику,
+// без токена (или с невалидным) запрос выполняется как анонимный
+func AuthOptional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, userType, msg := authenticate(c.GetHeader("Authorization")); msg == "" {
+			c.Set("user_id", userID)
+			c.Set("user_type", userType)
 		}
+		c.Next()
+	}
+}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Неверная структура токена"})
-			return
-		}
+// проверка токена из заголовка Authorization; при ошибке возвращает её текст
+func authenticate(header string) (uint, string, string) {
+	if !strings.HasPrefix(header, "Bearer ") {
+		return 0, "", "Отсутствует токен"
+	}
 
-		expUnix := int64(claims["exp"].(float64))
-		if time.Now().Unix() > expUnix {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Срок действия токена истёк"})
-			return
-		}
+	tokenStr := strings.TrimPrefix(header, "Bearer ")
 
-		userID := uint(claims["user_id"].(float64))
-		userType := claims["user_type"].(string)
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.GetJWTSecret()), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, "", "Невалидный токен"
+	}
 
-		// Проверка: есть ли такой пользователь в базе
-		var user models.User
-		if err := db.DB.First(&user, userID).Error; err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-			return
-		}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, "", "Неверная структура токена"
+	}
+	exp, okExp := claims["exp"].(float64)
+	rawID, okID := claims["user_id"].(float64)
+	userType, okType := claims["user_type"].(string)
+	if !okExp || !okID || !okType {
+		return 0, "", "Неверная структура токена"
+	}
 
-		c.Set("user_id", userID)
-		c.Set("user_type", userType)
-		c.Next()
+	if time.Now().Unix() > int64(exp) {
+		return 0, "", "Срок действия токена истёк"
 	}
+
+	// Проверка: есть ли такой пользователь в базе
+	userID := uint(rawID)
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		return 0, "", "Пользователь не найден"
+	}
+
+	return userID, userType, ""
 }
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"CinemaBooking/pkg/money"
+
 	"gorm.io/datatypes"
 )
 
@@ -22,6 +24,60 @@ const (
 	PaymentSpend   PaymentOperation = "spend"
 )
 
+// Статус платежа через платёжный шлюз
+type PaymentIntentStatus string
+
+const (
+	IntentPending       PaymentIntentStatus = "pending"        // ждёт подтверждения шлюзом
+	IntentSucceeded     PaymentIntentStatus = "succeeded"      // деньги получены и зачислены
+	IntentFailed        PaymentIntentStatus = "failed"         // отклонён шлюзом
+	IntentRefunded      PaymentIntentStatus = "refunded"       // оплачен, но зачислить не удалось — деньги возвращены на карту
+	IntentRefundPending PaymentIntentStatus = "refund_pending" // оплачен, но зачислить не удалось — возврат на карту ещё не прошёл
+)
+
+// Назначение платежа через шлюз
+type PaymentPurpose string
+
+const (
+	PurposeRefill  PaymentPurpose = "refill"  // пополнение баланса
+	PurposeBooking PaymentPurpose = "booking" // оплата брони картой
+)
+
+// Способ оплаты брони
+type PaymentMethod string
+
+const (
+	PayWallet PaymentMethod = "wallet" // с баланса кошелька
+	PayCard   PaymentMethod = "card"   // картой через платёжный шлюз
+)
+
+// Счёт в журнале проводок: у пользователя — кошелёк и бонусы, остальные счета общие для сервиса
+type LedgerAccountKind string
+
+const (
+	AccountWallet    LedgerAccountKind = "wallet"     // деньги пользователя на балансе
+	AccountBonus     LedgerAccountKind = "bonus"      // бонусы пользователя
+	AccountRevenue   LedgerAccountKind = "revenue"    // выручка от продажи билетов
+	AccountRefunds   LedgerAccountKind = "refunds"    // возвраты клиентам
+	AccountGateway   LedgerAccountKind = "gateway"    // деньги, прошедшие через платёжный шлюз
+	AccountBonusPool LedgerAccountKind = "bonus_pool" // выпуск и погашение бонусов
+	AccountOpening   LedgerAccountKind = "opening"    // входящие остатки на момент перехода на журнал
+	AccountDebt      LedgerAccountKind = "debt"       // долг пользователя: удержание, которое не покрыли ни бонусы, ни баланс (остаток отрицательный)
+)
+
+// Хозяйственная операция, к которой относится проводка
+type LedgerOperation string
+
+const (
+	LedgerRefill         LedgerOperation = "refill"          // пополнение баланса через шлюз
+	LedgerBookingPayment LedgerOperation = "booking_payment" // оплата брони или заказа
+	LedgerRefund         LedgerOperation = "refund"          // возврат при отмене
+	LedgerCharge         LedgerOperation = "charge"          // прочее списание с баланса
+	LedgerBonus          LedgerOperation = "bonus"           // начисление или списание бонусов вне брони
+	LedgerOpening        LedgerOperation = "opening"         // входящий остаток
+	LedgerLegacy         LedgerOperation = "legacy"          // операция из истории до журнала
+)
+
 type BonusOperation string
 
 const (
@@ -44,6 +100,46 @@ const (
 	ReviewRejected ReviewStatus = "rejected" // отменен
 )
 
+type SeatType string
+
+const (
+	SeatStandard   SeatType = "standard"
+	SeatVIP        SeatType = "vip"
+	SeatCouch      SeatType = "couch"      // диван на двоих, продаётся одним билетом
+	SeatWheelchair SeatType = "wheelchair" // место для зрителя на коляске
+)
+
+type SessionStatus string
+
+const (
+	SessionScheduled SessionStatus = "scheduled"
+	SessionCanceled  SessionStatus = "canceled" // отменён кинотеатром, билеты возвращены
+)
+
+// Что делать, если клиент младше возрастного ограничения сеанса
+type AgeCheckMode string
+
+const (
+	AgeCheckBlock AgeCheckMode = "block" // продажа запрещена
+	AgeCheckWarn  AgeCheckMode = "warn"  // продажа с предупреждением, возраст проверяют на входе
+)
+
+type SessionFormat string
+
+const (
+	Format2D     SessionFormat = "2d"
+	Format3D     SessionFormat = "3d"
+	FormatIMAX   SessionFormat = "imax"
+	FormatIMAX3D SessionFormat = "imax_3d"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending" // ждёт отправки
+	NotificationSent    NotificationStatus = "sent"
+)
+
 // Пользователи и авторизация
 type AuthCredential struct {
 	ID        uint       `json:"id" gorm:"primaryKey"`
@@ -61,13 +157,13 @@ type Profile struct {
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 
-	FirstName  string    `gorm:"type:varchar(50);not null"`
-	SecondName string    `gorm:"type:varchar(50)"`
-	Phone      string    `gorm:"type:varchar(11);unique;not null"`
-	Email      string    `gorm:"type:varchar(50)"`
-	BirthDay   time.Time `gorm:"type:date"`
-	Balance    float64   `gorm:"type:numeric(12,2);not null"`
-	Bonus      float64   `gorm:"type:numeric(12,2);not null"`
+	FirstName  string      `gorm:"type:varchar(50);not null"`
+	SecondName string      `gorm:"type:varchar(50)"`
+	Phone      string      `gorm:"type:varchar(11);unique;not null"`
+	Email      string      `gorm:"type:varchar(50)"`
+	BirthDay   time.Time   `gorm:"type:date"`
+	Balance    money.Money `gorm:"type:numeric(12,2);not null"` // кэш суммы движений по счёту wallet в журнале
+	Bonus      money.Money `gorm:"type:numeric(12,2);not null"` // кэш суммы движений по счёту bonus в журнале
 }
 
 type User struct {
@@ -94,6 +190,9 @@ type Cinema struct {
 	Location string `gorm:"type:varchar(100)"`
 	Phone    string `gorm:"type:varchar(11)"`
 	Email    string `gorm:"type:varchar(50)"`
+	// часовой пояс IANA: время сеансов хранится в UTC, а показывается и вводится в местном времени
+	Timezone string       `gorm:"type:varchar(64);not null;default:'Europe/Moscow'"`
+	AgeCheck AgeCheckMode `gorm:"type:varchar(10);not null;default:'block'"`
 }
 
 type HallType struct {
@@ -112,13 +211,15 @@ type CinemaHall struct {
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 
-	CinemaID   uint
+	CinemaID   uint `gorm:"index"`
 	Cinema     Cinema
-	HallTypeID uint
+	HallTypeID uint `gorm:"index"`
 	HallType   HallType
 	Name       string `gorm:"type:varchar(50);not null"`
 	Capacity   uint
 	Structure  datatypes.JSON `gorm:"type:jsonb"`
+	// перерыв после сеанса (уборка, реклама) в минутах; nil — значение по умолчанию из конфигурации
+	BufferMinutes *uint
 }
 
 // Фильмы
@@ -165,33 +266,153 @@ type Session struct {
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 
-	FilmID    uint `gorm:"not null"`
+	// индексы под выборки расписания и поиск: по фильму, по залу и по времени начала
+	FilmID    uint `gorm:"not null;index:idx_session_film_start,priority:1"`
 	Film      Film
-	HallID    uint `gorm:"not null"`
+	HallID    uint `gorm:"not null;index:idx_session_hall_start,priority:1"`
 	Hall      CinemaHall
-	StartTime time.Time `gorm:"not null"`
-	Price     float64   `gorm:"type:numeric(12,2);not null"`
+	StartTime time.Time      `gorm:"not null;index:idx_session_film_start,priority:2;index:idx_session_hall_start,priority:2;index:idx_session_start"`
+	Price     money.Money    `gorm:"type:numeric(12,2);not null"` // базовая цена (обычное место)
+	Prices    []SessionPrice `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE;"`
+	SeriesID  *uint          `gorm:"index"` // серия, из которой создан сеанс
+	SessionAttributes
+	Surcharge money.Money `gorm:"type:numeric(12,2);not null;default:0"` // наценка за формат, добавляется к цене любого места
+
+	Status       SessionStatus `gorm:"type:varchar(20);not null;default:'scheduled'"`
+	CanceledAt   *time.Time
+	CancelReason string `gorm:"type:varchar(255)"`
+}
+
+// Параметры показа: формат, язык звука и субтитров, поздний сеанс только для взрослых
+type SessionAttributes struct {
+	Format           SessionFormat `gorm:"type:varchar(10);not null;default:'2d';index"`
+	AudioLanguage    string        `gorm:"type:varchar(8);not null;default:'ru'"` // код языка ISO 639-1
+	SubtitleLanguage string        `gorm:"type:varchar(8)"`                       // пусто — без субтитров
+	LateShow         bool          `gorm:"not null;default:false"`                // поздний сеанс 18+
+}
+
+// Наценка за формат показа, подставляется в новые сеансы этого формата
+type FormatSurcharge struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	Format SessionFormat `gorm:"type:varchar(10);not null;uniqueIndex"`
+	Amount money.Money   `gorm:"type:numeric(12,2);not null"`
+}
+
+// Серия сеансов по шаблону: фильм в зале в заданные часы по дням недели на период
+type SessionSeries struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	FilmID   uint `gorm:"not null"`
+	Film     Film
+	HallID   uint `gorm:"not null"`
+	Hall     CinemaHall
+	Slots    datatypes.JSON `gorm:"type:jsonb"` // время начала: ["10:00", "14:30"]
+	Weekdays datatypes.JSON `gorm:"type:jsonb"` // дни недели: 1 — понедельник, 7 — воскресенье
+	DateFrom time.Time      `gorm:"type:date;not null"`
+	DateTo   time.Time      `gorm:"type:date;not null"`
+	Price    money.Money    `gorm:"type:numeric(12,2);not null"`
+	SessionAttributes
+}
+
+// Цена категории мест на сеансе: либо своя цена, либо множитель к базовой
+type SessionPrice struct {
+	ID         uint         `gorm:"primaryKey"`
+	SessionID  uint         `gorm:"not null;uniqueIndex:idx_session_seat_type"`
+	SeatType   SeatType     `gorm:"type:varchar(20);not null;uniqueIndex:idx_session_seat_type"`
+	Price        *money.Money `gorm:"type:numeric(12,2)"`
+	MultiplierBP *int64       `gorm:"column:multiplier_bp"` // множитель в базисных пунктах: 15000 — ×1.5
 }
 
 type Booking struct {
-	ID uint `gorm:"primaryKey"`
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	OrderID *uint `gorm:"index"` // заказ, если место куплено в составе нескольких
+
+	// уникальность места среди активных броней — частичный индекс idx_seat_active (см. db.Migrate)
+	SessionID  uint `gorm:"not null;index"`
+	Session    Session
+	CustomerID uint `gorm:"not null;index"`
+	Customer   User
+
+	RowNum  uint `gorm:"not null"`
+	SeatNum uint `gorm:"not null"`
+
+	SpendBonus    money.Money `gorm:"type:numeric(12,2);default:0"`
+	ReceivedBonus money.Money `gorm:"type:numeric(12,2);default:0"`
+	TotalPrice    money.Money `gorm:"type:numeric(12,2);not null"`
+
+	Status        BookingStatus `gorm:"type:varchar(20);not null"`
+	ExpiresAt     *time.Time    // до какого момента держится неоплаченная бронь
+	PaymentMethod PaymentMethod `gorm:"type:varchar(10);not null;default:'wallet'"`
+
+	CanceledAt     *time.Time
+	CanceledBy     *uint       // кто отменил: сам клиент или администратор
+	CancelReason   string      `gorm:"type:varchar(255)"`
+	RefundedAmount money.Money `gorm:"type:numeric(12,2);default:0"`
+
+	AgeWarning string         `gorm:"-"` // предупреждение о возрасте при продаже, в базе не хранится
+	Payment    *PaymentIntent `gorm:"-"` // платёж картой, созданный вместе с бронью
+}
+
+// Заказ из нескольких мест на один сеанс: оплачивается и отменяется целиком
+type Order struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 
-	SessionID  uint `gorm:"not null;index:idx_seat,unique"`
+	SessionID  uint `gorm:"not null"`
 	Session    Session
 	CustomerID uint `gorm:"not null"`
 	Customer   User
+	Bookings   []Booking
 
-	RowNum  uint `gorm:"not null;index:idx_seat,unique"`
-	SeatNum uint `gorm:"not null;index:idx_seat,unique"`
+	SpendBonus    money.Money `gorm:"type:numeric(12,2);default:0"`
+	ReceivedBonus money.Money `gorm:"type:numeric(12,2);default:0"`
+	TotalPrice    money.Money `gorm:"type:numeric(12,2);not null"`
 
-	SpendBonus    float64 `gorm:"type:numeric(12,2);default:0"`
-	ReceivedBonus float64 `gorm:"type:numeric(12,2);default:0"`
-	TotalPrice    float64 `gorm:"type:numeric(12,2);not null"`
+	Status         BookingStatus `gorm:"type:varchar(20);not null"`
+	RefundedAmount money.Money   `gorm:"type:numeric(12,2);default:0"`
 
-	Status BookingStatus `gorm:"type:varchar(20);not null"`
+	AgeWarning string `gorm:"-"` // предупреждение о возрасте при продаже, в базе не хранится
+}
+
+// Политика отмены: проценты возврата в зависимости от времени до начала сеанса.
+// Без кинотеатра и типа зала действует для всех, иначе — только для указанных.
+type CancellationPolicy struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	Name       string `gorm:"type:varchar(50);not null"`
+	CinemaID   *uint  `gorm:"index"`
+	Cinema     *Cinema
+	HallTypeID *uint `gorm:"index"`
+	HallType   *HallType
+	Rules      []CancellationRule `gorm:"foreignKey:PolicyID;constraint:OnDelete:CASCADE;"`
+}
+
+// Правило политики: при отмене не позднее чем за MinHoursBefore часов возвращается RefundPercent %
+type CancellationRule struct {
+	ID uint `gorm:"primaryKey"`
+
+	PolicyID       uint    `gorm:"not null;index"`
+	MinHoursBefore float64 `gorm:"type:numeric(6,2);not null"`
+	RefundPercent  uint    `gorm:"not null"`
 }
 
 // Финансы
+// История платежей до перехода на журнал проводок; больше не пишется, переносится в журнал при запуске
 type PaymentHistory struct {
 	ID        uint       `json:"id" gorm:"primaryKey"`
 	CreatedAt time.Time  `json:"created_at"`
@@ -200,11 +421,45 @@ type PaymentHistory struct {
 
 	UserID    uint
 	User      User
-	Amount    float64          `gorm:"type:numeric(12,2)"`
+	Amount    money.Money      `gorm:"type:numeric(12,2)"`
 	Desc      string           `gorm:"type:varchar(50)"`
 	Operation PaymentOperation `gorm:"type:varchar(20);not null"`
 }
 
+// Платёж через платёжный шлюз. Деньги зачисляются только после подтверждения шлюзом
+type PaymentIntent struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	UserID   uint `gorm:"not null;index"`
+	User     User
+	Purpose  PaymentPurpose `gorm:"type:varchar(20);not null"`
+	Amount   money.Money    `gorm:"type:numeric(12,2);not null"`
+	Currency money.Currency `gorm:"type:varchar(3);not null;default:'RUB'"`
+
+	BookingID *uint `gorm:"index"` // бронь, если платёж — её оплата картой
+
+	Provider   string              `gorm:"type:varchar(20);not null"`
+	ExternalID string              `gorm:"type:varchar(64);not null;uniqueIndex"` // ID платежа у провайдера
+	Status     PaymentIntentStatus `gorm:"type:varchar(20);not null;default:'pending';index"`
+
+	FailureReason string `gorm:"type:varchar(255)"`
+	CompletedAt   *time.Time
+}
+
+// Обработанное уведомление платёжного шлюза: по уникальному EventID повторы отбрасываются
+type PaymentEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	EventID    string `gorm:"type:varchar(64);not null;uniqueIndex"` // ID события у провайдера
+	Type       string `gorm:"type:varchar(40);not null"`
+	ExternalID string `gorm:"type:varchar(64);not null;index"` // ID платежа у провайдера
+}
+
+// История бонусов до перехода на журнал проводок; больше не пишется, переносится в журнал при запуске
 type BonusHistory struct {
 	ID        uint       `json:"id" gorm:"primaryKey"`
 	CreatedAt time.Time  `json:"created_at"`
@@ -213,11 +468,63 @@ type BonusHistory struct {
 
 	UserID    uint
 	User      User
-	Amount    float64        `gorm:"type:numeric(12,2);not null"`
+	Amount    money.Money    `gorm:"type:numeric(12,2);not null"`
 	Desc      string         `gorm:"type:varchar(50)"`
 	Operation BonusOperation `gorm:"type:varchar(20);not null"`
 }
 
+// Счёт журнала проводок. У общих счетов UserID = 0
+type LedgerAccount struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Kind   LedgerAccountKind `gorm:"type:varchar(20);not null;uniqueIndex:idx_ledger_account"`
+	UserID uint              `gorm:"not null;default:0;uniqueIndex:idx_ledger_account"`
+}
+
+// Проводка: набор движений по счетам, сумма которых по деньгам и по бонусам равна нулю.
+// Журнал только дополняется — исправление делается новой проводкой (см. db.Migrate)
+type LedgerEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Operation   LedgerOperation `gorm:"type:varchar(30);not null"`
+	Description string          `gorm:"type:varchar(255);not null"`
+	UserID      uint            `gorm:"not null;default:0;index"` // чья операция; 0 — служебная
+
+	BookingID       *uint `gorm:"index"`
+	OrderID         *uint `gorm:"index"`
+	PaymentIntentID *uint `gorm:"index"`
+
+	Postings []LedgerPosting `gorm:"foreignKey:EntryID"`
+}
+
+// Движение по счёту: плюс — приход на счёт, минус — расход
+type LedgerPosting struct {
+	ID        uint `gorm:"primaryKey"`
+	EntryID   uint `gorm:"not null;index"`
+	AccountID uint `gorm:"not null;index"`
+	Account   LedgerAccount
+
+	Amount money.Money `gorm:"type:numeric(12,2);not null"`
+}
+
+// Уведомление пользователю; копится в очереди и отправляется фоновым процессом
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	UserID uint `gorm:"not null;index"`
+	User   User
+	Kind   string             `gorm:"type:varchar(50);not null"`
+	Title  string             `gorm:"type:varchar(255);not null"`
+	Body   string             `gorm:"type:text"`
+	Status NotificationStatus `gorm:"type:varchar(20);not null;index"`
+	SentAt *time.Time
+}
+
 type FilmGenre struct {
 	FilmID  uint `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
 	GenreID uint `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
@@ -0,0 +1,48 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Код валюты по ISO 4217
+type Currency string
+
+const RUB Currency = "RUB"
+
+// Единственная валюта сервиса. Цены сеансов, брони, заказы, балансы профилей и проводки журнала
+// хранятся без кода валюты и всегда считаются в ней; код есть только у платежей через шлюз и в ответах API.
+// Сумма в другой валюте на входе отклоняется (RequireDefault), поэтому смешать валюты в расчётах нельзя
+const Default = RUB
+
+var ErrInvalidCurrency = errors.New("некорректный код валюты")
+
+// Разобрать код валюты: три латинские буквы, регистр не важен
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return Currency(code), nil
+}
+
+// Проверить, что код — валюта сервиса; пустой код означает валюту сервиса
+func RequireDefault(code Currency) error {
+	if code == "" {
+		return nil
+	}
+	c, err := ParseCurrency(string(code))
+	if err != nil {
+		return err
+	}
+	if c != Default {
+		return fmt.Errorf("%w: поддерживается только %s", ErrInvalidCurrency, Default)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+// Денежные суммы без ошибок округления float64: сумма хранится целым числом копеек.
+// В базе суммы лежат в колонках numeric(12,2), в JSON — числом с двумя знаками после точки.
+//
+// Правила округления заданы явно: входные суммы точнее копейки отклоняются (ErrPrecision),
+// а при умножении на долю (проценты, коэффициенты) половина копейки округляется от нуля
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Сумма в копейках
+type Money int64
+
+// копеек в рубле
+const minorPerUnit = 100
+
+// базисных пунктов в единице множителя: 15000 — это ×1.5
+const BasisPointsPerUnit = 10000
+
+var (
+	ErrInvalid        = errors.New("некорректная денежная сумма")
+	ErrPrecision      = errors.New("сумма указана точнее копейки")
+	ErrDivisionByZero = errors.New("деление суммы на ноль")
+	ErrOverflow       = errors.New("сумма выходит за допустимый диапазон")
+)
+
+// Сумма из копеек
+func FromMinor(minor int64) Money {
+	return Money(minor)
+}
+
+// Сумма из float64 с округлением до копейки (половина — от нуля). Только для значений,
+// которые уже пришли как float64: числа из JSON-карт обновлений, результаты внешних API
+func FromFloat(v float64) Money {
+	return Money(math.Round(v * minorPerUnit))
+}
+
+// Сумма из float64, которая должна быть точной до копейки (числа из JSON-карт обновлений)
+func FromFloatExact(v float64) (Money, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) || math.Abs(v) > math.MaxInt64/minorPerUnit {
+		return 0, ErrInvalid
+	}
+	minor := math.Round(v * minorPerUnit)
+	if math.Abs(v*minorPerUnit-minor) > 1e-6 {
+		return 0, fmt.Errorf("%w: %v", ErrPrecision, v)
+	}
+	return Money(minor), nil
+}
+
+// Разобрать сумму вида "350", "350.5", "-12.05" без потери точности
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalid
+	}
+
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	units, frac, _ := strings.Cut(s, ".")
+	if units == "" && frac == "" {
+		return 0, ErrInvalid
+	}
+	for _, part := range []string{units, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("%w: %s", ErrInvalid, s)
+			}
+		}
+	}
+	// незначащие нули в конце дробной части не влияют на точность: 350.500 == 350.50
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("%w: %s", ErrPrecision, s)
+	}
+
+	var whole int64
+	if units != "" {
+		var err error
+		if whole, err = strconv.ParseInt(units, 10, 64); err != nil || whole > math.MaxInt64/minorPerUnit-1 {
+			return 0, fmt.Errorf("%w: %s", ErrInvalid, s)
+		}
+	}
+	var minor int64
+	if frac != "" {
+		minor, _ = strconv.ParseInt((frac + "0")[:2], 10, 64)
+	}
+
+	m := Money(whole*minorPerUnit + minor)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+// Копейки
+func (m Money) Minor() int64 {
+	return int64(m)
+}
+
+// Значение для внешних интерфейсов, которые принимают только float64; в расчётах не использовать
+func (m Money) Float64() float64 {
+	return float64(m) / minorPerUnit
+}
+
+// Сумма с двумя знаками после точки: "350.50", "-0.05"
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/minorPerUnit, v%minorPerUnit)
+}
+
+// Умножить на дробь num/den; половина копейки округляется от нуля.
+// Произведение считается в big.Int, поэтому промежуточного переполнения нет; нулевой знаменатель —
+// ErrDivisionByZero, результат, который не помещается в Money, — ErrOverflow
+func (m Money) MulRatio(num, den int64) (Money, error) {
+	if den == 0 {
+		return 0, ErrDivisionByZero
+	}
+	q := ratio(m, big.NewInt(num), big.NewInt(den))
+	if !q.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return Money(q.Int64()), nil
+}
+
+// Процент от суммы с округлением до копейки. Вернуть или начислить больше самой суммы нельзя,
+// поэтому процент больше 100 считается как 100, и результат всегда помещается в Money
+func (m Money) Percent(p uint) Money {
+	if p > 100 {
+		p = 100
+	}
+	return Money(ratio(m, big.NewInt(int64(p)), big.NewInt(100)).Int64())
+}
+
+// Умножить на множитель в базисных пунктах (15000 — ×1.5); половина копейки округляется от нуля
+func (m Money) MulBasisPoints(bp int64) (Money, error) {
+	return m.MulRatio(bp, BasisPointsPerUnit)
+}
+
+// Разделить сумму на части пропорционально весам; остаток от округления достаётся последней части,
+// поэтому сумма частей всегда равна исходной. Веса неотрицательные: при отрицательном весе,
+// как и при нулевых, сумма делится поровну
+func (m Money) Allocate(weights []Money) []Money {
+	n := len(weights)
+	sum := new(big.Int)
+	for _, w := range weights {
+		if w < 0 {
+			return m.Split(n)
+		}
+		sum.Add(sum, big.NewInt(int64(w)))
+	}
+	if sum.Sign() == 0 {
+		return m.Split(n)
+	}
+
+	parts := make([]Money, n)
+	var assigned Money
+	for i := 0; i < n-1; i++ {
+		parts[i] = Money(ratio(m, big.NewInt(int64(weights[i])), sum).Int64())
+		assigned += parts[i]
+	}
+	parts[n-1] = m - assigned
+	return parts
+}
+
+// Разделить сумму на n равных частей; остаток от округления достаётся последней
+func (m Money) Split(n int) []Money {
+	parts := make([]Money, n)
+	if n == 0 {
+		return parts
+	}
+
+	share := Money(ratio(m, big.NewInt(1), big.NewInt(int64(n))).Int64())
+	for i := 0; i < n-1; i++ {
+		parts[i] = share
+	}
+	parts[n-1] = m - share*Money(n-1)
+	return parts
+}
+
+// Сумма без знака
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Меньшая из сумм
+func Min(a, b Money) Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// В JSON — числом: 350.50
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// Из JSON принимается число или строка; сумма точнее копейки — ошибка
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalid, s)
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Из параметров запроса (gin)
+func (m *Money) UnmarshalParam(param string) error {
+	v, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// В базу — строкой numeric: "350.50"
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Из базы: numeric приходит строкой, целые и float64 — от выражений без явного типа
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Money(v * minorPerUnit)
+	case float64:
+		*m = FromFloat(v)
+	default:
+		return fmt.Errorf("%w: неподдерживаемый тип %T", ErrInvalid, src)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// m * num / den в big.Int с округлением половины от нуля (den не ноль). При 0 <= num <= den
+// результат по модулю не больше m — так доли считают Percent, Allocate и Split, им проверка не нужна
+func ratio(m Money, num, den *big.Int) *big.Int {
+	p := new(big.Int).Mul(big.NewInt(int64(m)), num)
+	d := new(big.Int).Set(den)
+	if d.Sign() < 0 {
+		p.Neg(p)
+		d.Neg(d)
+	}
+	q, r := new(big.Int).QuoRem(p, d, new(big.Int))
+	if r.Lsh(r.Abs(r), 1).Cmp(d) >= 0 {
+		if p.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
@@ -0,0 +1,314 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{in: "350", want: 350_00},
+		{in: "350.5", want: 350_50},
+		{in: "350.50", want: 350_50},
+		{in: "350.500", want: 350_50}, // незначащие нули не влияют на точность
+		{in: "0.01", want: 1},
+		{in: ".5", want: 50},
+		{in: "5.", want: 5_00},
+		{in: "+12", want: 12_00},
+		{in: " 7.25 ", want: 7_25},
+		{in: "-12.05", want: -12_05},
+		{in: "-0.01", want: -1},
+		{in: "0", want: 0},
+
+		{in: "1.005", err: ErrPrecision},
+		{in: "-0.001", err: ErrPrecision},
+		{in: "0.125", err: ErrPrecision},
+
+		{in: "", err: ErrInvalid},
+		{in: "-", err: ErrInvalid},
+		{in: ".", err: ErrInvalid},
+		{in: "abc", err: ErrInvalid},
+		{in: "1.2.3", err: ErrInvalid},
+		{in: "1,50", err: ErrInvalid},
+		{in: "1e5", err: ErrInvalid},
+		{in: "--1", err: ErrInvalid},
+		{in: "12a", err: ErrInvalid},
+		{in: "99999999999999999999", err: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Parse(%q): ошибка %v, ожидалась %v", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %d, %v; ожидалось %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{350_50, "350.50"},
+		{-5, "-0.05"},
+		{-12_05, "-12.05"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, ожидалось %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestMulRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		num, den int64
+		want     Money
+	}{
+		{"без округления", 100_00, 1, 4, 25_00},
+		{"меньше половины вниз", 10, 1, 3, 3},
+		{"больше половины вверх", 20, 1, 3, 7},
+		{"половина от нуля", 5, 1, 2, 3},
+		{"половина от нуля у отрицательной", -5, 1, 2, -3},
+		{"отрицательная вниз по модулю", -10, 1, 3, -3},
+		{"отрицательный знаменатель", 5, 1, -2, -3},
+		{"10% от 350.55", 350_55, 10, 100, 35_06},
+		{"10% от -350.55", -350_55, 10, 100, -35_06},
+		{"ноль", 0, 7, 9, 0},
+		{"произведение больше int64", Money(math.MaxInt64 / 10), 10, 10, Money(math.MaxInt64 / 10)},
+	}
+	for _, tt := range tests {
+		if got, err := tt.m.MulRatio(tt.num, tt.den); err != nil || got != tt.want {
+			t.Errorf("%s: %d × %d/%d = %d (%v), ожидалось %d", tt.name, int64(tt.m), tt.num, tt.den, got, err, tt.want)
+		}
+	}
+}
+
+func TestMulRatioErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		num, den int64
+		want     error
+	}{
+		{"деление на ноль", 1, 1, 0, ErrDivisionByZero},
+		{"переполнение", Money(math.MaxInt64), 2, 1, ErrOverflow},
+		{"переполнение в минус", Money(math.MinInt64), 1, -1, ErrOverflow},
+	}
+	for _, tt := range tests {
+		if _, err := tt.m.MulRatio(tt.num, tt.den); !errors.Is(err, tt.want) {
+			t.Errorf("%s: ошибка %v, ожидалась %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestPercentAndBasisPoints(t *testing.T) {
+	if got := Money(350_55).Percent(10); got != 35_06 {
+		t.Errorf("10%% от 350.55 = %s", got)
+	}
+	if got := Money(100_00).Percent(0); got != 0 {
+		t.Errorf("0%% от 100 = %s", got)
+	}
+	if got := Money(100_00).Percent(250); got != 100_00 {
+		t.Errorf("250%% от 100 = %s, ожидалось не больше самой суммы", got)
+	}
+	if got, err := Money(333).MulBasisPoints(12500); err != nil || got != 416 { // 4.1625 → 4.16
+		t.Errorf("3.33 × 1.25 = %s (%v)", got, err)
+	}
+	if got, err := Money(300_00).MulBasisPoints(15000); err != nil || got != 450_00 {
+		t.Errorf("300 × 1.5 = %s (%v)", got, err)
+	}
+	if _, err := Money(math.MaxInt64 / 2).MulBasisPoints(30000); !errors.Is(err, ErrOverflow) {
+		t.Errorf("переполнение при умножении на ×3: %v", err)
+	}
+}
+
+func sum(parts []Money) Money {
+	var s Money
+	for _, p := range parts {
+		s += p
+	}
+	return s
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		weights []Money
+		want    []Money
+	}{
+		{"поровну с остатком", 10_00, []Money{1, 1, 1}, []Money{3_33, 3_33, 3_34}},
+		{"пропорционально", 100_00, []Money{300_00, 100_00}, []Money{75_00, 25_00}},
+		{"нулевые веса — поровну", 10_00, []Money{0, 0}, []Money{5_00, 5_00}},
+		{"одна часть", 7_77, []Money{5}, []Money{7_77}},
+		{"отрицательная сумма", -10_00, []Money{1, 1, 1}, []Money{-3_33, -3_33, -3_34}},
+		{"отрицательный вес — поровну", 10_00, []Money{5, -1}, []Money{5_00, 5_00}},
+		{"сумма весов больше int64", 10_00, []Money{math.MaxInt64, math.MaxInt64}, []Money{5_00, 5_00}},
+		{"крупные суммы и веса", Money(math.MaxInt64 / 4), []Money{Money(math.MaxInt64 / 4), Money(math.MaxInt64 / 4)}, nil},
+	}
+	for _, tt := range tests {
+		parts := tt.m.Allocate(tt.weights)
+		if len(parts) != len(tt.weights) {
+			t.Errorf("%s: частей %d, ожидалось %d", tt.name, len(parts), len(tt.weights))
+			continue
+		}
+		if s := sum(parts); s != tt.m {
+			t.Errorf("%s: сумма частей %s, ожидалось %s", tt.name, s, tt.m)
+		}
+		if tt.want == nil {
+			continue
+		}
+		for i := range parts {
+			if parts[i] != tt.want[i] {
+				t.Errorf("%s: части %v, ожидалось %v", tt.name, parts, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for _, tt := range []struct {
+		m Money
+		n int
+	}{{10_00, 3}, {1, 3}, {0, 4}, {-10_00, 3}, {99_99, 7}, {5_00, 1}} {
+		parts := tt.m.Split(tt.n)
+		if len(parts) != tt.n {
+			t.Errorf("Split(%s, %d): частей %d", tt.m, tt.n, len(parts))
+			continue
+		}
+		if s := sum(parts); s != tt.m {
+			t.Errorf("Split(%s, %d): сумма частей %s", tt.m, tt.n, s)
+		}
+		for _, p := range parts[:tt.n-1] {
+			if p != parts[0] {
+				t.Errorf("Split(%s, %d): неравные части %v", tt.m, tt.n, parts)
+				break
+			}
+		}
+	}
+	if parts := Money(10).Split(0); len(parts) != 0 {
+		t.Errorf("Split на 0 частей: %v", parts)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type payload struct {
+		Amount Money  `json:"amount"`
+		Price  *Money `json:"price,omitempty"`
+	}
+
+	// туда и обратно без потерь
+	for _, m := range []Money{0, 1, 350_50, -12_05, 12_345_678_90} {
+		b, err := json.Marshal(payload{Amount: m})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got payload
+		if err := json.Unmarshal(b, &got); err != nil || got.Amount != m {
+			t.Errorf("%s → %s → %s, %v", m, b, got.Amount, err)
+		}
+	}
+
+	if b, _ := json.Marshal(payload{Amount: 350_50}); string(b) != `{"amount":350.50}` {
+		t.Errorf("JSON: %s", b)
+	}
+
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{in: `{"amount": 350.5}`, want: 350_50},
+		{in: `{"amount": "350.50"}`, want: 350_50},
+		{in: `{"amount": 1.5e2}`, want: 150_00},
+		{in: `{"amount": -0.05}`, want: -5},
+		{in: `{"amount": null}`, want: 0},
+		{in: `{"amount": 0.001}`, err: ErrPrecision},
+		{in: `{"amount": "abc"}`, err: ErrInvalid},
+		{in: `{"amount": true}`, err: ErrInvalid},
+	}
+	for _, tt := range tests {
+		var got payload
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: ошибка %v, ожидалась %v", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got.Amount != tt.want {
+			t.Errorf("%s = %s, %v; ожидалось %s", tt.in, got.Amount, err, tt.want)
+		}
+	}
+}
+
+func TestFromFloatExact(t *testing.T) {
+	if m, err := FromFloatExact(0.1 + 0.2); err != nil || m != 30 {
+		t.Errorf("0.1+0.2 = %s, %v", m, err)
+	}
+	if m, err := FromFloatExact(350.5); err != nil || m != 350_50 {
+		t.Errorf("350.5 = %s, %v", m, err)
+	}
+	if _, err := FromFloatExact(0.125); !errors.Is(err, ErrPrecision) {
+		t.Errorf("0.125: %v", err)
+	}
+	for _, v := range []float64{math.NaN(), math.Inf(1), 1e300} {
+		if _, err := FromFloatExact(v); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%v: %v", v, err)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		src  interface{}
+		want Money
+	}{
+		{[]byte("123.40"), 123_40},
+		{"-0.05", -5},
+		{int64(7), 7_00},
+		{float64(1.1), 1_10},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		var m Money
+		if err := m.Scan(tt.src); err != nil || m != tt.want {
+			t.Errorf("Scan(%v) = %s, %v; ожидалось %s", tt.src, m, err, tt.want)
+		}
+	}
+	var m Money
+	if err := m.Scan(true); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Scan(bool): %v", err)
+	}
+}
+
+func TestRequireDefault(t *testing.T) {
+	for _, ok := range []Currency{"", "RUB", "rub", " RUB "} {
+		if err := RequireDefault(ok); err != nil {
+			t.Errorf("RequireDefault(%q): %v", ok, err)
+		}
+	}
+	for _, bad := range []Currency{"USD", "RU", "RUBL", "R1B"} {
+		if err := RequireDefault(bad); !errors.Is(err, ErrInvalidCurrency) {
+			t.Errorf("RequireDefault(%q): %v", bad, err)
+		}
+	}
+}
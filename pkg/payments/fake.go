@@ -0,0 +1,160 @@
+package payments
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"CinemaBooking/pkg/money"
+)
+
+// копейки суммы, при которых фейковый провайдер отклоняет платёж (100.13 — отказ банка)
+const declineCents = 13
+
+// Фейковый провайдер в памяти процесса. Подтверждение проходит сразу: успешно, кроме сумм
+// с 13 копейками. OnUpdate (если задан) вызывается после каждого изменения статуса — так фейк
+// заменяет уведомление от настоящего шлюза
+type Fake struct {
+	OnUpdate func(intentID string)
+
+	mu      sync.Mutex
+	intents map[string]*Intent
+	byRef   map[string]string
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		intents: make(map[string]*Intent),
+		byRef:   make(map[string]string),
+	}
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) CreateIntent(req IntentRequest) (*Intent, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: сумма должна быть больше нуля", ErrInvalidInput)
+	}
+	if _, err := money.ParseCurrency(string(req.Currency)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.Reference != "" {
+		if id, ok := f.byRef[req.Reference]; ok {
+			return f.copyOf(id), nil
+		}
+	}
+
+	id, err := randomID("pi_fake")
+	if err != nil {
+		return nil, err
+	}
+	intent := &Intent{
+		ID:        id,
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Status:    StatusPending,
+	}
+	f.intents[intent.ID] = intent
+	if req.Reference != "" {
+		f.byRef[req.Reference] = intent.ID
+	}
+	return f.copyOf(intent.ID), nil
+}
+
+func (f *Fake) Confirm(intentID string) (*Intent, error) {
+	f.mu.Lock()
+	intent, ok := f.intents[intentID]
+	if !ok {
+		f.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	changed := false
+	switch intent.Status {
+	case StatusPending:
+		if cents(intent.Amount) == declineCents {
+			intent.Status = StatusFailed
+			intent.FailureReason = "платёж отклонён банком"
+		} else {
+			intent.Status = StatusSucceeded
+		}
+		changed = true
+	case StatusSucceeded:
+		// повторное подтверждение ничего не меняет
+	default:
+		f.mu.Unlock()
+		return nil, ErrInvalidState
+	}
+	result := f.copyOf(intentID)
+	f.mu.Unlock()
+
+	if changed {
+		f.notify(intentID)
+	}
+	return result, nil
+}
+
+func (f *Fake) Refund(intentID string, amount money.Money) (*Intent, error) {
+	f.mu.Lock()
+	intent, ok := f.intents[intentID]
+	if !ok {
+		f.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	if intent.Status != StatusSucceeded {
+		f.mu.Unlock()
+		return nil, ErrInvalidState
+	}
+	rest := intent.Amount - intent.Refunded
+	if amount <= 0 || amount > rest {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("%w: вернуть можно не больше %s", ErrInvalidInput, rest)
+	}
+	intent.Refunded += amount
+	if intent.Refunded >= intent.Amount {
+		intent.Status = StatusRefunded
+	}
+	result := f.copyOf(intentID)
+	f.mu.Unlock()
+
+	f.notify(intentID)
+	return result, nil
+}
+
+func (f *Fake) Status(intentID string) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.intents[intentID]; !ok {
+		return nil, ErrNotFound
+	}
+	return f.copyOf(intentID), nil
+}
+
+// копия платежа, чтобы вызывающий не менял состояние провайдера (под f.mu)
+func (f *Fake) copyOf(id string) *Intent {
+	c := *f.intents[id]
+	return &c
+}
+
+// уведомление уходит асинхронно, как от настоящего шлюза
+func (f *Fake) notify(intentID string) {
+	if f.OnUpdate != nil {
+		go f.OnUpdate(intentID)
+	}
+}
+
+// случайный ID с префиксом: счётчик в памяти после перезапуска начинался бы заново
+// и повторял ID, которые уже сохранены в базе
+func randomID(prefix string) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + "_" + hex.EncodeToString(buf), nil
+}
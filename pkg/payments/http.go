@@ -0,0 +1,112 @@
+package payments
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"CinemaBooking/pkg/money"
+)
+
+// таймаут запроса к шлюзу
+const httpTimeout = 10 * time.Second
+
+// Клиент платёжного шлюза по HTTP (протокол — как у локальной заглушки, см. StandIn)
+type HTTPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPProvider(baseURL string) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: httpTimeout},
+	}
+}
+
+func (p *HTTPProvider) Name() string { return "http" }
+
+func (p *HTTPProvider) CreateIntent(req IntentRequest) (*Intent, error) {
+	return p.do(http.MethodPost, "/intents", req)
+}
+
+func (p *HTTPProvider) Confirm(intentID string) (*Intent, error) {
+	return p.do(http.MethodPost, "/intents/"+url.PathEscape(intentID)+"/confirm", nil)
+}
+
+func (p *HTTPProvider) Refund(intentID string, amount money.Money) (*Intent, error) {
+	return p.do(http.MethodPost, "/intents/"+url.PathEscape(intentID)+"/refund", refundRequest{Amount: amount})
+}
+
+func (p *HTTPProvider) Status(intentID string) (*Intent, error) {
+	return p.do(http.MethodGet, "/intents/"+url.PathEscape(intentID), nil)
+}
+
+type refundRequest struct {
+	Amount money.Money `json:"amount"`
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// запрос к шлюзу; ответы 404, 409 и 400 превращаются в ErrNotFound, ErrInvalidState и ErrInvalidInput
+func (p *HTTPProvider) do(method, path string, body interface{}) (*Intent, error) {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("платёжный шлюз недоступен: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e errorBody
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return nil, ErrNotFound
+		case http.StatusConflict:
+			return nil, remoteError(ErrInvalidState, e.Error)
+		case http.StatusBadRequest:
+			return nil, remoteError(ErrInvalidInput, e.Error)
+		}
+		return nil, errors.New("ошибка платёжного шлюза: " + resp.Status)
+	}
+
+	var intent Intent
+	if err := json.NewDecoder(resp.Body).Decode(&intent); err != nil {
+		return nil, fmt.Errorf("некорректный ответ платёжного шлюза: %w", err)
+	}
+	return &intent, nil
+}
+
+// ошибка шлюза поверх нашей: текст шлюза без повтора текста нашей ошибки
+func remoteError(base error, msg string) error {
+	msg = strings.TrimPrefix(strings.TrimPrefix(msg, base.Error()), ": ")
+	if msg == "" {
+		return base
+	}
+	return fmt.Errorf("%w: %s", base, msg)
+}
@@ -0,0 +1,77 @@
+// Платёжный шлюз: интерфейс провайдера и его реализации — фейковый провайдер в памяти
+// (для тестов и локального запуска) и HTTP-клиент к внешнему шлюзу или его локальной заглушке (cmd/paystub).
+// О смене статуса шлюз сообщает подписанными событиями (webhook.go)
+package payments
+
+import (
+	"errors"
+	"fmt"
+
+	"CinemaBooking/pkg/money"
+)
+
+// Статус платежа у провайдера
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // создан, ждёт подтверждения или обработки
+	StatusSucceeded Status = "succeeded" // деньги списаны с карты
+	StatusFailed    Status = "failed"    // отклонён
+	StatusRefunded  Status = "refunded"  // возвращён полностью
+)
+
+// Ошибки провайдера
+var (
+	ErrNotFound     = errors.New("платёж не найден у провайдера")
+	ErrInvalidState = errors.New("операция недоступна в текущем статусе платежа")
+	ErrInvalidInput = errors.New("некорректные данные платежа")
+)
+
+// Запрос на создание платежа
+type IntentRequest struct {
+	Amount      money.Money    `json:"amount"`
+	Currency    money.Currency `json:"currency"`
+	Description string         `json:"description"`
+	Reference   string         `json:"reference"` // наш идентификатор, повторный запрос с ним вернёт тот же платёж
+}
+
+// Платёж у провайдера
+type Intent struct {
+	ID            string         `json:"id"`
+	Reference     string         `json:"reference"`
+	Amount        money.Money    `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+	Refunded      money.Money    `json:"refunded"`
+	Status        Status         `json:"status"`
+	FailureReason string         `json:"failure_reason,omitempty"`
+}
+
+// Провайдер платежей. Confirm может вернуть платёж ещё в статусе pending —
+// окончательный результат тогда придёт уведомлением, а Status позволяет его перепроверить
+type Provider interface {
+	Name() string
+	CreateIntent(req IntentRequest) (*Intent, error)
+	Confirm(intentID string) (*Intent, error)
+	Refund(intentID string, amount money.Money) (*Intent, error)
+	Status(intentID string) (*Intent, error)
+}
+
+// Провайдер по имени из конфигурации: fake — в памяти процесса, http — внешний шлюз по адресу baseURL
+func New(name, baseURL string) (Provider, error) {
+	switch name {
+	case "", "fake":
+		return NewFake(), nil
+	case "http":
+		if baseURL == "" {
+			return nil, errors.New("не задан адрес платёжного шлюза")
+		}
+		return NewHTTPProvider(baseURL), nil
+	default:
+		return nil, fmt.Errorf("неизвестный платёжный провайдер %q", name)
+	}
+}
+
+// копейки в сумме: 13 у 100.13
+func cents(amount money.Money) int64 {
+	return amount.Minor() % 100
+}
@@ -0,0 +1,144 @@
+package payments
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// сколько раз заглушка пытается доставить уведомление
+const webhookAttempts = 5
+
+// Локальная заглушка платёжного шлюза: HTTP API поверх фейкового провайдера.
+// После каждого изменения статуса платежа отправляет на webhookURL подписанное событие (см. Event, Sign)
+//
+//	POST /intents              — создать платёж
+//	GET  /intents/{id}         — статус
+//	POST /intents/{id}/confirm — подтвердить (оплата клиентом)
+//	POST /intents/{id}/refund  — вернуть {"amount": ...}
+type StandIn struct {
+	fake       *Fake
+	webhookURL string
+	secret     string
+	client     *http.Client
+}
+
+func NewStandIn(webhookURL, secret string) *StandIn {
+	s := &StandIn{
+		fake:       NewFake(),
+		webhookURL: webhookURL,
+		secret:     secret,
+		client:     &http.Client{Timeout: httpTimeout},
+	}
+	s.fake.OnUpdate = s.sendEvent
+	return s
+}
+
+func (s *StandIn) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /intents", func(w http.ResponseWriter, r *http.Request) {
+		var req IntentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorBody{Error: err.Error()})
+			return
+		}
+		s.reply(w, http.StatusCreated)(s.fake.CreateIntent(req))
+	})
+	mux.HandleFunc("GET /intents/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.reply(w, http.StatusOK)(s.fake.Status(r.PathValue("id")))
+	})
+	mux.HandleFunc("POST /intents/{id}/confirm", func(w http.ResponseWriter, r *http.Request) {
+		s.reply(w, http.StatusOK)(s.fake.Confirm(r.PathValue("id")))
+	})
+	mux.HandleFunc("POST /intents/{id}/refund", func(w http.ResponseWriter, r *http.Request) {
+		var req refundRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorBody{Error: err.Error()})
+			return
+		}
+		s.reply(w, http.StatusOK)(s.fake.Refund(r.PathValue("id"), req.Amount))
+	})
+	return mux
+}
+
+// ответ заглушки: платёж или ошибка с кодом, который HTTPProvider переведёт обратно в ошибку
+func (s *StandIn) reply(w http.ResponseWriter, okStatus int) func(*Intent, error) {
+	return func(intent *Intent, err error) {
+		switch {
+		case err == nil:
+			writeJSON(w, okStatus, intent)
+		case errors.Is(err, ErrNotFound):
+			writeJSON(w, http.StatusNotFound, errorBody{Error: err.Error()})
+		case errors.Is(err, ErrInvalidState):
+			writeJSON(w, http.StatusConflict, errorBody{Error: err.Error()})
+		default:
+			writeJSON(w, http.StatusBadRequest, errorBody{Error: err.Error()})
+		}
+	}
+}
+
+// событие о смене статуса платежа; при ошибке доставка повторяется с растущей паузой.
+// ID события при повторах не меняется, подпись ставится заново на каждую попытку
+func (s *StandIn) sendEvent(intentID string) {
+	if s.webhookURL == "" {
+		return
+	}
+	intent, err := s.fake.Status(intentID)
+	if err != nil {
+		return
+	}
+
+	// ID события случайный: после перезапуска заглушки новые события не должны совпасть
+	// со старыми, иначе сервис отбросит их как повторы
+	id, err := randomID("evt")
+	if err != nil {
+		log.Printf("Не удалось создать событие по платежу %s: %v", intentID, err)
+		return
+	}
+	event := Event{
+		ID:      id,
+		Type:    EventType(intent.Status),
+		Created: time.Now().Unix(),
+		Intent:  *intent,
+	}
+	body, _ := json.Marshal(event)
+
+	delay := time.Second
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		err := s.post(body)
+		if err == nil {
+			return
+		}
+		log.Printf("Событие %s по платежу %s не доставлено (попытка %d): %v", event.ID, intentID, attempt, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (s *StandIn) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(s.secret, body, time.Now()))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.New(resp.Status)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
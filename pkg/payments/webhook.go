@@ -0,0 +1,80 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// заголовок с подписью уведомления: t=<unix-время>,v1=<hex HMAC-SHA256 от "t.тело">
+const SignatureHeader = "X-Payment-Signature"
+
+// насколько подпись может отличаться по времени: защита от повтора старых перехваченных уведомлений
+const SignatureTolerance = 5 * time.Minute
+
+var ErrInvalidSignature = errors.New("неверная подпись уведомления")
+
+// Уведомление шлюза о платеже. ID события уникален: повтор того же события приходит с тем же ID
+type Event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"` // payment.<статус>
+	Created int64  `json:"created"`
+	Intent  Intent `json:"data"`
+}
+
+// тип события по статусу платежа
+func EventType(status Status) string {
+	return "payment." + string(status)
+}
+
+// Подписать тело уведомления; результат — значение заголовка SignatureHeader
+func Sign(secret string, body []byte, at time.Time) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+// Проверить подпись уведомления и её время
+func VerifySignature(secret, header string, body []byte, now time.Time) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	if ts == "" || sig == "" {
+		return fmt.Errorf("%w: нет времени или подписи", ErrInvalidSignature)
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: некорректное время", ErrInvalidSignature)
+	}
+	if diff := now.Sub(time.Unix(unix, 0)); diff > SignatureTolerance || diff < -SignatureTolerance {
+		return fmt.Errorf("%w: подпись устарела", ErrInvalidSignature)
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
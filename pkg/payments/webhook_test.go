@@ -0,0 +1,51 @@
+package payments
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded"}`)
+	now := time.Unix(1_700_000_000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+		now    time.Time
+		ok     bool
+	}{
+		{name: "верная подпись", header: Sign(secret, body, now), body: body, now: now, ok: true},
+		{name: "пробелы между частями", header: "t=" + ts + ", v1=" + signature(secret, ts, body), body: body, now: now, ok: true},
+		{name: "в пределах допуска", header: Sign(secret, body, now.Add(-SignatureTolerance)), body: body, now: now, ok: true},
+		{name: "чужой секрет", header: Sign("other", body, now), body: body, now: now},
+		{name: "изменённое тело", header: Sign(secret, body, now), body: []byte(`{"id":"evt_2"}`), now: now},
+		{name: "подпись от другого времени", header: "t=" + strconv.FormatInt(now.Unix()+1, 10) + ",v1=" + signature(secret, ts, body), body: body, now: now},
+		{name: "нет t", header: "v1=" + signature(secret, ts, body), body: body, now: now},
+		{name: "нет v1", header: "t=" + ts, body: body, now: now},
+		{name: "пустой заголовок", header: "", body: body, now: now},
+		{name: "время не число", header: "t=abc,v1=" + signature(secret, "abc", body), body: body, now: now},
+		{name: "устаревшая подпись", header: Sign(secret, body, now.Add(-SignatureTolerance-time.Second)), body: body, now: now},
+		{name: "подпись из будущего", header: Sign(secret, body, now.Add(SignatureTolerance+time.Second)), body: body, now: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(secret, tt.header, tt.body, tt.now)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("ожидалась верная подпись, ошибка: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("ожидалась ErrInvalidSignature, получено: %v", err)
+			}
+		})
+	}
+}
@@ -34,8 +34,14 @@ func SetupRouter() *gin.Engine {
 		wallet.GET("/balance", userHandlers.GetBalanceHandler)
 		wallet.GET("/payments", userHandlers.GetMyPaymentsHandler)
 		wallet.POST("/refill", userHandlers.RefillMyBalanceHandler)
+		wallet.GET("/refills", userHandlers.GetMyRefillsHandler)
+		wallet.GET("/refills/:id", userHandlers.GetMyRefillHandler)
+		wallet.POST("/refills/:id/confirm", userHandlers.ConfirmRefillHandler)
 	}
 
+	//  PAYMENTS (уведомления платёжного шлюза)
+	r.POST("/payments/webhook", handlers.PaymentWebhookHandler)
+
 	//  BONUS
 	bonus := r.Group("/bonus", middleware.AuthRequired())
 	{
@@ -43,10 +49,13 @@ func SetupRouter() *gin.Engine {
 		bonus.GET("/history", userHandlers.GetBonusHistoryHandler)
 	}
 
+	//  NOTIFICATIONS
+	r.GET("/notifications", middleware.AuthRequired(), userHandlers.GetMyNotificationsHandler)
+
 	//  FILMS
 	films := r.Group("/films")
 	{
-		films.GET("", userHandlers.GetAllFilmsHandler)
+		films.GET("", middleware.AuthOptional(), userHandlers.GetAllFilmsHandler)
 		films.GET("/:id", userHandlers.GetFilmHandler)
 		films.GET("/:id/genres", userHandlers.GetGenresByFilmHandler)
 		films.GET("/:id/reviews", userHandlers.GetReviewsByFilmHandler)
@@ -65,20 +74,45 @@ func SetupRouter() *gin.Engine {
 	//  POSTERS
 	r.GET("/posters", userHandlers.GetAllPostersHandler)
 
+	//  CINEMAS
+	cinemas := r.Group("/cinemas")
+	{
+		cinemas.GET("", userHandlers.GetAllCinemasHandler)
+		cinemas.GET("/:id", userHandlers.GetCinemaHandler)
+		cinemas.GET("/:id/sessions", userHandlers.GetCinemaScheduleHandler)
+	}
+
 	//  SESSIONS
 	sessions := r.Group("/sessions")
 	{
-		sessions.GET("", userHandlers.GetAllSessionsHandler)
-		sessions.GET("/film/:id", userHandlers.GetSessionsByFilmHandler)
+		sessions.GET("", middleware.AuthOptional(), userHandlers.GetAllSessionsHandler)
+		sessions.GET("/search", middleware.AuthOptional(), userHandlers.SearchSessionsHandler)
+		sessions.GET("/film/:id", middleware.AuthOptional(), userHandlers.GetSessionsByFilmHandler)
 		sessions.GET("/:id/seats", userHandlers.GetAvailableSeatsHandler)
+		sessions.GET("/:id/prices", userHandlers.GetSessionPricesHandler)
 	}
 
 	//  BOOKINGS
 	bookings := r.Group("/bookings", middleware.AuthRequired())
 	{
+		bookings.GET("", userHandlers.GetMyBookingsHandler)
+		bookings.GET("/:id", userHandlers.GetMyBookingHandler)
+		bookings.GET("/:id/refund-preview", userHandlers.PreviewBookingRefundHandler)
 		bookings.POST("", userHandlers.CreateBookingHandler)
-		bookings.DELETE("/:id", adminHandlers.CancelBookingHandler)
-		// можно добавить GET /bookings для истории броней
+		bookings.POST("/hold", userHandlers.HoldSeatHandler)
+		bookings.POST("/:id/confirm", userHandlers.ConfirmBookingHandler)
+		bookings.GET("/:id/payment", userHandlers.GetBookingPaymentHandler)
+		bookings.POST("/:id/payment/confirm", userHandlers.ConfirmBookingPaymentHandler)
+		bookings.DELETE("/:id", userHandlers.CancelMyBookingHandler)
+	}
+
+	//  ORDERS (несколько мест одним заказом)
+	orders := r.Group("/orders", middleware.AuthRequired())
+	{
+		orders.POST("", userHandlers.CreateOrderHandler)
+		orders.GET("/:id", userHandlers.GetOrderHandler)
+		orders.GET("/:id/refund-preview", userHandlers.PreviewOrderRefundHandler)
+		orders.DELETE("/:id", userHandlers.CancelOrderHandler)
 	}
 
 	//  ADMIN
@@ -98,14 +132,54 @@ func SetupRouter() *gin.Engine {
 
 		// сеансы
 		admin.POST("/sessions", adminHandlers.CreateSessionHandler)
+		admin.POST("/sessions/validate", adminHandlers.ValidateSessionHandler)
 		admin.PATCH("/sessions/:id", adminHandlers.UpdateSessionHandler)
 		admin.DELETE("/sessions/:id", adminHandlers.DeleteSessionHandler)
+		admin.POST("/sessions/:id/cancel", adminHandlers.CancelSessionHandler)
+		admin.PUT("/sessions/:id/prices", adminHandlers.SetSessionPricesHandler)
+		admin.GET("/format-surcharges", adminHandlers.GetFormatSurchargesHandler)
+		admin.PUT("/format-surcharges/:format", adminHandlers.SetFormatSurchargeHandler)
+
+		// серии сеансов по шаблону
+		admin.GET("/session-series", adminHandlers.GetSessionSeriesListHandler)
+		admin.GET("/session-series/:id", adminHandlers.GetSessionSeriesHandler)
+		admin.POST("/session-series", adminHandlers.CreateSessionSeriesHandler)
+		admin.POST("/session-series/preview", adminHandlers.PreviewSessionSeriesHandler)
+		admin.PATCH("/session-series/:id", adminHandlers.UpdateSessionSeriesHandler)
+		admin.DELETE("/session-series/:id", adminHandlers.DeleteSessionSeriesHandler)
+
+		// кинотеатры, типы залов и залы
+		admin.GET("/cinemas", adminHandlers.GetCinemasHandler)
+		admin.POST("/cinemas", adminHandlers.CreateCinemaHandler)
+		admin.PATCH("/cinemas/:id", adminHandlers.UpdateCinemaHandler)
+		admin.DELETE("/cinemas/:id", adminHandlers.DeleteCinemaHandler)
+		admin.GET("/hall-types", adminHandlers.GetHallTypesHandler)
+		admin.POST("/hall-types", adminHandlers.CreateHallTypeHandler)
+		admin.PATCH("/hall-types/:id", adminHandlers.UpdateHallTypeHandler)
+		admin.DELETE("/hall-types/:id", adminHandlers.DeleteHallTypeHandler)
+		admin.GET("/cinemas/:id/halls", adminHandlers.GetCinemaHallsHandler)
+		admin.POST("/cinemas/:id/halls", adminHandlers.CreateHallHandler)
+		admin.PATCH("/cinemas/:id/halls/:hall_id", adminHandlers.UpdateHallHandler)
+		admin.DELETE("/cinemas/:id/halls/:hall_id", adminHandlers.DeleteHallHandler)
+		admin.PUT("/halls/:id/structure", adminHandlers.UpdateHallStructureHandler)
 
 		// афиши
 		admin.POST("/posters", adminHandlers.CreatePosterHandler)
 		admin.PATCH("/posters/:id", adminHandlers.UpdatePosterHandler)
 		admin.DELETE("/posters/:id", adminHandlers.DeletePosterHandler)
 
+		// бронирования клиентов
+		admin.GET("/bookings", adminHandlers.SearchBookingsHandler)
+		admin.POST("/bookings/:id/cancel", adminHandlers.CancelBookingHandler)
+
+		// журнал проводок
+		admin.GET("/ledger/check", adminHandlers.CheckLedgerHandler)
+
+		// политики отмены
+		admin.GET("/cancellation-policies", adminHandlers.GetCancellationPoliciesHandler)
+		admin.POST("/cancellation-policies", adminHandlers.CreateCancellationPolicyHandler)
+		admin.DELETE("/cancellation-policies/:id", adminHandlers.DeleteCancellationPolicyHandler)
+
 		// модерация отзывов
 		admin.PATCH("/reviews/:id/approve", adminHandlers.ApproveReviewHandler)
 		admin.PATCH("/reviews/:id/reject", adminHandlers.RejectReviewHandler)
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// возрастное ограничение сеанса в SQL: рейтинг фильма, для позднего сеанса — 18+ (в запросе нужен JOIN film)
+const sessionMinAgeExpr = "GREATEST(film.age_rating, CASE WHEN session.late_show THEN ? ELSE 0 END)"
+
+// ____________________________________________________INTERNAL____________________________________________________
+// проверка возраста клиента перед продажей. Возраст считается на дату сеанса по времени кинотеатра.
+// Если кинотеатр продаёт с предупреждением, вместо ошибки возвращается текст предупреждения.
+// Сеанс должен быть загружен с фильмом и кинотеатром
+func checkAgeRating(profile *models.Profile, session *models.Session) (string, error) {
+	minAge := sessionMinAge(session)
+	if minAge == 0 {
+		return "", nil
+	}
+
+	var problem string
+	if profile.BirthDay.IsZero() {
+		problem = fmt.Sprintf("сеанс %d+, а дата рождения в профиле не указана", minAge)
+	} else {
+		age := ageOn(profile.BirthDay, session.StartTime.In(sessionLocation(session)))
+		if age >= minAge {
+			return "", nil
+		}
+		problem = fmt.Sprintf("сеанс %d+, а на дату сеанса вам %d", minAge, age)
+	}
+
+	if session.Hall.Cinema.AgeCheck == models.AgeCheckWarn {
+		return "Возрастное ограничение: " + problem + ". На входе могут попросить документ", nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrAgeRestricted, problem)
+}
+
+// возрастное ограничение сеанса: рейтинг фильма, для позднего сеанса — не меньше 18
+func sessionMinAge(session *models.Session) int {
+	minAge := int(session.Film.AgeRating)
+	if session.LateShow && minAge < lateShowAge {
+		minAge = lateShowAge
+	}
+	return minAge
+}
+
+// полных лет на дату day (день рождения 29 февраля в невисокосный год наступает 1 марта)
+func ageOn(birth, day time.Time) int {
+	age := day.Year() - birth.Year()
+	if day.Month() < birth.Month() || (day.Month() == birth.Month() && day.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
+
+// дата рождения зрителя для фильтра «можно по возрасту»; нулевая, если не указана
+func viewerBirthDay(userID uint) (time.Time, error) {
+	var user models.User
+	if err := db.DB.Preload("Profile").First(&user, userID).Error; err != nil {
+		return time.Time{}, errors.New("пользователь не найден")
+	}
+	return user.Profile.BirthDay, nil
+}
+
+// только сеансы, на которые зрителю можно по возрасту на дату сеанса.
+// Без даты рождения остаются только сеансы без ограничения
+func applyAgeFilter(query *gorm.DB, birth time.Time) *gorm.DB {
+	if birth.IsZero() {
+		return query.Where(sessionMinAgeExpr+" = 0", lateShowAge)
+	}
+	return query.Where("date_part('year', age(("+sessionLocalTimeExpr+")::date, ?::date)) >= "+sessionMinAgeExpr,
+		birth.Format("2006-01-02"), lateShowAge)
+}
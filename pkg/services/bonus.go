@@ -1,93 +1,78 @@
 package services
 
 import (
-	"fmt"
-
 	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
 	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
 
 	"gorm.io/gorm"
 )
 
 // Получить текущий баланс бонусов
-func GetBonusBalance(userID uint) (float64, error) {
+func GetBonusBalance(userID uint) (money.Money, error) {
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		return 0, err
+	}
+
 	var profile models.Profile
-	if err := db.DB.First(&profile, "id = ?", userID).Error; err != nil {
+	if err := db.DB.First(&profile, user.ProfileID).Error; err != nil {
 		return 0, err
 	}
 	return profile.Bonus, nil
 }
 
-// Получить историю бонусов
-func GetBonusHistory(userID uint) ([]models.BonusHistory, error) {
-	var history []models.BonusHistory
-
-	if err := db.DB.
-		Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Find(&history).Error; err != nil {
+// Получить историю бонусов: движения по бонусному счёту в журнале, новые сверху
+func GetBonusHistory(userID uint) ([]dt.BonusHistoryItemDTO, error) {
+	rows, err := accountHistory(models.AccountBonus, userID)
+	if err != nil {
 		return nil, err
 	}
 
-	return history, nil
+	result := make([]dt.BonusHistoryItemDTO, 0, len(rows))
+	for _, r := range rows {
+		op := models.BonusEarn
+		if r.Amount < 0 {
+			op = models.BonusRedeem
+		}
+		result = append(result, dt.BonusHistoryItemDTO{
+			ID:        r.ID,
+			Amount:    r.Amount,
+			Desc:      r.Description,
+			Operation: string(op),
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return result, nil
 }
 
 // ____________________________________________________INTERNAL____________________________________________________
 // Добавить бонусы
-func AddBonus(userID uint, amount float64, description string) error {
+func AddBonus(userID uint, amount money.Money, description string) error {
 	return db.DB.Transaction(func(tx *gorm.DB) error {
-		// Обновляем баланс
-		if err := tx.Model(&models.Profile{}).
-			Where("id = ?", userID).
-			Update("bonus", gorm.Expr("bonus + ?", amount)).Error; err != nil {
-			return err
-		}
-
-		// Пишем в историю
-		history := models.BonusHistory{
-			UserID:    userID,
-			Amount:    amount,
-			Desc:      description,
-			Operation: models.BonusEarn,
-		}
-		if err := tx.Create(&history).Error; err != nil {
-			return err
-		}
-
-		return nil
+		return post(tx, models.LedgerEntry{
+			Operation:   models.LedgerBonus,
+			Description: description,
+			UserID:      userID,
+		},
+			posting{models.AccountBonusPool, 0, -amount},
+			posting{models.AccountBonus, userID, amount},
+		)
 	})
 }
 
 // Списать бонусы
-func SpendBonus(userID uint, amount float64, description string) error {
+func SpendBonus(userID uint, amount money.Money, description string) error {
 	return db.DB.Transaction(func(tx *gorm.DB) error {
-		// Проверяем баланс
-		var profile models.Profile
-		if err := tx.First(&profile, "id = ?", userID).Error; err != nil {
-			return err
-		}
-		if profile.Bonus < amount {
-			return fmt.Errorf("недостаточно бонусов")
-		}
-
-		// Списываем
-		if err := tx.Model(&models.Profile{}).
-			Where("id = ?", userID).
-			Update("bonus", gorm.Expr("bonus - ?", amount)).Error; err != nil {
-			return err
-		}
-
-		// Записываем в историю
-		history := models.BonusHistory{
-			UserID:    userID,
-			Amount:    -amount,
-			Desc:      description,
-			Operation: models.BonusRedeem,
-		}
-		if err := tx.Create(&history).Error; err != nil {
-			return err
-		}
-
-		return nil
+		// Не хватает бонусов — ErrInsufficientBonus
+		return post(tx, models.LedgerEntry{
+			Operation:   models.LedgerBonus,
+			Description: description,
+			UserID:      userID,
+		},
+			posting{models.AccountBonus, userID, -amount},
+			posting{models.AccountBonusPool, 0, amount},
+		)
 	})
 }
@@ -1,121 +1,221 @@
 package services
 
 import (
+	"CinemaBooking/config"
 	"CinemaBooking/pkg/db"
 	"CinemaBooking/pkg/dt"
 	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// Забронировать билет
+// Забронировать билет. С баланса бронь оплачивается сразу; при оплате картой место удерживается,
+// после фиксации брони создаётся платёж в шлюзе, и бронь становится оплаченной только после его подтверждения
 func CreateBooking(input dt.CreateBookingDTI) (*models.Booking, error) {
 	var booking models.Booking
 
 	err := db.DB.Transaction(func(tx *gorm.DB) error {
 
-		// 1. Загружаем пользователя и профиль
-		var user models.User
-		if err := tx.First(&user, input.UserID).Error; err != nil {
-			return errors.New("пользователь не найден")
+		// 1. Загружаем сеанс и проверяем место по схеме зала.
+		// Сеанс блокируется раньше профиля — в том же порядке, что и при отмене сеанса
+		session, err := loadBookableSession(tx, input.SessionID)
+		if err != nil {
+			return err
 		}
-		var profile models.Profile
-		if err := tx.First(&profile, user.ProfileID).Error; err != nil {
-			return errors.New("профиль не найден")
+		seat, err := validateSeat(session, input.RowNum, input.SeatNum)
+		if err != nil {
+			return err
 		}
 
-		// 2. Проверка занятости места
-		taken, err := isSeatTaken(tx, input.SessionID, input.RowNum, input.SeatNum)
+		// 2. Загружаем и блокируем профиль пользователя, проверяем возраст
+		profile, err := lockProfile(tx, input.UserID)
 		if err != nil {
 			return err
 		}
-		if taken {
-			return errors.New("место занято")
+		warning, err := checkAgeRating(profile, session)
+		if err != nil {
+			return err
 		}
 
-		// 3. Загружаем сеанс
-		var session models.Session
-		if err := tx.First(&session, input.SessionID).Error; err != nil {
-			return errors.New("сеанс не найден")
+		// 3. Занимаем место по цене его категории (занятое другим — ErrSeatTaken)
+		price, err := seatPrice(session, seat.Type)
+		if err != nil {
+			return err
+		}
+		SpendBonus, ReceivedBonus, TotalPrice := calcBookingPrice(price, profile.Bonus, input.UseBonus)
+		booking = models.Booking{
+			SessionID:     input.SessionID,
+			CustomerID:    input.UserID,
+			RowNum:        input.RowNum,
+			SeatNum:       input.SeatNum,
+			SpendBonus:    SpendBonus,
+			ReceivedBonus: ReceivedBonus,
+			TotalPrice:    TotalPrice,
+			Status:        models.BookingPaid,
+			PaymentMethod: models.PayWallet,
+			AgeWarning:    warning,
 		}
-		var ReceivedBonus, SpendBonus, TotalPrice float64
 
-		if input.UseBonus {
+		// 4а. Оплата картой: место ждёт подтверждения платежа (полностью покрытую бонусами бронь платить картой нечем)
+		if input.PaymentMethod == models.PayCard && TotalPrice > 0 {
+			expiresAt := time.Now().Add(config.GetBookingHoldTTL())
+			booking.Status = models.BookingReserved
+			booking.ExpiresAt = &expiresAt
+			booking.PaymentMethod = models.PayCard
+			return reserveSeat(tx, &booking)
+		}
 
-			ReceivedBonus = 0.0
+		// 4б. Оплата с баланса: списываем средства сразу
+		if err := reserveSeat(tx, &booking); err != nil {
+			return err
+		}
+		if err := chargeForBooking(tx, input.UserID, SpendBonus, ReceivedBonus, TotalPrice, models.LedgerEntry{
+			Description: fmt.Sprintf("Оплата брони #%d", booking.ID),
+			BookingID:   &booking.ID,
+		}); err != nil {
+			return err
+		}
 
-			if profile.Bonus > session.Price {
-				SpendBonus = session.Price
-				TotalPrice = 0.0
-			} else {
-				TotalPrice = session.Price - profile.Bonus
-				SpendBonus = profile.Bonus
-			}
-		} else {
+		return nil
+	})
 
-			ReceivedBonus = session.Price * 0.1
-			TotalPrice = session.Price
-			SpendBonus = 0.0
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Платёж картой создаём уже после фиксации брони, чтобы запрос к шлюзу не держал блокировки места и профиля.
+	// Если шлюз не ответил, место сразу освобождается; если процесс упал раньше — бронь снимет ReleaseExpiredHolds
+	if booking.PaymentMethod == models.PayCard {
+		desc := fmt.Sprintf("Оплата брони #%d", booking.ID)
+		booking.Payment, err = createPaymentIntent(input.UserID, models.PurposeBooking, &booking.ID, booking.TotalPrice, desc)
+		if err != nil {
+			if releaseErr := releaseUnpaidBooking(booking.ID, input.UserID); releaseErr != nil {
+				log.Printf("Не удалось снять бронь #%d без платежа: %v", booking.ID, releaseErr)
+			}
+			return nil, err
 		}
+	}
+	return &booking, nil
+}
+
+// Временно удержать место без оплаты
+func HoldSeat(input dt.HoldSeatDTI) (*models.Booking, error) {
+	var booking models.Booking
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
 
-		// 6. Обновляем баланс пользователя
-		if err := tx.Model(&profile).
-			Update("bonus", gorm.Expr("bonus - ? + ?", SpendBonus, ReceivedBonus)).Error; err != nil {
+		// 1. Проверяем пользователя, сеанс и возраст
+		var user models.User
+		if err := tx.Preload("Profile").First(&user, input.UserID).Error; err != nil {
+			return errors.New("пользователь не найден")
+		}
+		session, err := loadBookableSession(tx, input.SessionID)
+		if err != nil {
+			return err
+		}
+		warning, err := checkAgeRating(&user.Profile, session)
+		if err != nil {
 			return err
 		}
-		if err := tx.Model(&profile).
-			Update("balance", gorm.Expr("balance - ?", TotalPrice)).Error; err != nil {
+		if _, err := validateSeat(session, input.RowNum, input.SeatNum); err != nil {
 			return err
 		}
 
-		// 7. Создаём запись о бронировании
+		// 2. Занимаем место со сроком удержания
+		expiresAt := time.Now().Add(config.GetBookingHoldTTL())
 		booking = models.Booking{
-			SessionID:     input.SessionID,
-			CustomerID:    input.UserID,
-			RowNum:        input.RowNum,
-			SeatNum:       input.SeatNum,
-			SpendBonus:    SpendBonus,
-			ReceivedBonus: ReceivedBonus,
-			TotalPrice:    TotalPrice,
-			Status:        models.BookingPaid,
+			SessionID:  input.SessionID,
+			CustomerID: input.UserID,
+			RowNum:     input.RowNum,
+			SeatNum:    input.SeatNum,
+			Status:     models.BookingReserved,
+			ExpiresAt:  &expiresAt,
+			AgeWarning: warning,
 		}
-		if err := tx.Create(&booking).Error; err != nil {
+		if err := reserveSeat(tx, &booking); err != nil {
 			return err
 		}
 
-		// 8. Записываем историю оплат
-		if TotalPrice > 0 {
-			payment := models.PaymentHistory{
-				UserID:    input.UserID,
-				Amount:    TotalPrice,
-				Operation: models.PaymentSpend,
-			}
-			if err := tx.Create(&payment).Error; err != nil {
-				return err
-			}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// Подтвердить удержанное место и оплатить его
+func ConfirmBooking(bookingID, userID uint, useBonus bool) (*models.Booking, error) {
+	var booking models.Booking
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+
+		// 1. Находим бронь
+		if err := tx.First(&booking, bookingID).Error; err != nil {
+			return errors.New("бронирование не найдено")
+		}
+		if booking.CustomerID != userID {
+			return errors.New("нельзя подтвердить чужое бронирование")
+		}
+		if booking.Status != models.BookingReserved {
+			return errors.New("бронирование нельзя подтвердить")
+		}
+		// бронь под оплату картой ждёт свой платёж: списание с баланса оплатило бы её второй раз
+		if booking.PaymentMethod == models.PayCard {
+			return errors.New("бронирование ожидает оплаты картой")
+		}
+		if isHoldExpired(&booking, time.Now()) {
+			return errors.New("время удержания места истекло")
 		}
 
-		// 9. Записываем историю бонусов (раздельно списание и начисление)
-		if SpendBonus > 0 {
-			bonusSpend := models.BonusHistory{
-				UserID:    input.UserID,
-				Amount:    SpendBonus,
-				Operation: models.BonusRedeem,
-			}
-			if err := tx.Create(&bonusSpend).Error; err != nil {
-				return err
-			}
+		// 2. Загружаем сеанс и блокируем профиль (сначала сеанс, как при отмене сеанса)
+		session, err := loadBookableSession(tx, booking.SessionID)
+		if err != nil {
+			return err
 		}
-		if ReceivedBonus > 0 {
-			bonusEarn := models.BonusHistory{
-				UserID:    input.UserID,
-				Amount:    ReceivedBonus,
-				Operation: models.BonusEarn,
-			}
-			if err := tx.Create(&bonusEarn).Error; err != nil {
-				return err
-			}
+		profile, err := lockProfile(tx, userID)
+		if err != nil {
+			return err
+		}
+		// режим проверки возраста мог смениться, пока место было удержано
+		if booking.AgeWarning, err = checkAgeRating(profile, session); err != nil {
+			return err
+		}
+		seat, err := validateSeat(session, booking.RowNum, booking.SeatNum)
+		if err != nil {
+			return err
+		}
+
+		// 3. Считаем стоимость по категории места и списываем средства
+		price, err := seatPrice(session, seat.Type)
+		if err != nil {
+			return err
+		}
+		SpendBonus, ReceivedBonus, TotalPrice := calcBookingPrice(price, profile.Bonus, useBonus)
+		if err := chargeForBooking(tx, userID, SpendBonus, ReceivedBonus, TotalPrice, models.LedgerEntry{
+			Description: fmt.Sprintf("Оплата брони #%d", booking.ID),
+			BookingID:   &booking.ID,
+		}); err != nil {
+			return err
+		}
+
+		// 4. Переводим бронь в оплаченную
+		booking.SpendBonus = SpendBonus
+		booking.ReceivedBonus = ReceivedBonus
+		booking.TotalPrice = TotalPrice
+		booking.Status = models.BookingPaid
+		booking.ExpiresAt = nil
+		booking.PaymentMethod = models.PayWallet
+		if err := tx.Save(&booking).Error; err != nil {
+			return err
 		}
 
 		return nil
@@ -127,8 +227,7 @@ func CreateBooking(input dt.CreateBookingDTI) (*models.Booking, error) {
 	return &booking, nil
 }
 
-// ____________________________________________________ADMIN_ONLY____________________________________________________
-// Отменить бронирование
+// Отменить своё бронирование
 func CancelBooking(bookingID, userID uint) error {
 	return db.DB.Transaction(func(tx *gorm.DB) error {
 
@@ -141,115 +240,364 @@ func CancelBooking(bookingID, userID uint) error {
 		if booking.CustomerID != userID {
 			return errors.New("нельзя отменить чужое бронирование")
 		}
-		if booking.Status != models.BookingPaid {
+		if booking.OrderID != nil {
+			return errors.New("место входит в заказ, отмените заказ целиком")
+		}
+		if booking.Status != models.BookingPaid && booking.Status != models.BookingReserved {
 			return errors.New("бронирование нельзя отменить")
 		}
 
-		// Загружаем пользователя и профиль
-		var user models.User
-		if err := tx.First(&user, booking.CustomerID).Error; err != nil {
-			return errors.New("пользователь не найден")
+		// Неоплаченную бронь снимаем без возврата, оплаченную — по политике отмены
+		var quote *refundQuote
+		if booking.Status == models.BookingPaid {
+			var err error
+			quote, err = quoteRefund(tx, booking.SessionID, booking.TotalPrice, booking.SpendBonus, booking.ReceivedBonus, time.Now())
+			if err != nil {
+				return err
+			}
 		}
-		var profile models.Profile
-		if err := tx.First(&profile, user.ProfileID).Error; err != nil {
-			return errors.New("профиль не найден")
+
+		return cancelBooking(tx, &booking, userID, "", quote)
+	})
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Найти бронирования по сеансу, пользователю, статусу и дате сеанса (местной для кинотеатра)
+func SearchBookings(filter dt.AdminBookingFilterDTI) (*dt.AdminBookingListDTO, error) {
+	page, limit := normalizePage(filter.Page, filter.Limit)
+
+	query := db.DB.Model(&models.Booking{}).
+		Joins("JOIN session ON session.id = booking.session_id").
+		Joins("JOIN cinema_hall ON cinema_hall.id = session.hall_id").
+		Joins("JOIN cinema ON cinema.id = cinema_hall.cinema_id")
+
+	if filter.SessionID != 0 {
+		query = query.Where("booking.session_id = ?", filter.SessionID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("booking.customer_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("booking.status = ?", filter.Status)
+	}
+	if filter.DateFrom != "" {
+		from, err := time.Parse("2006-01-02", filter.DateFrom)
+		if err != nil {
+			return nil, errors.New("неверный формат даты, ожидается YYYY-MM-DD")
 		}
+		query = query.Where(sessionLocalTimeExpr+" >= ?", from.Format("2006-01-02"))
+	}
+	if filter.DateTo != "" {
+		to, err := time.Parse("2006-01-02", filter.DateTo)
+		if err != nil {
+			return nil, errors.New("неверный формат даты, ожидается YYYY-MM-DD")
+		}
+		query = query.Where(sessionLocalTimeExpr+" < ?", to.AddDate(0, 0, 1).Format("2006-01-02")) // включительно
+	}
+	query = query.Session(&gorm.Session{})
 
-		// Возвращаем деньги
-		if booking.TotalPrice > 0 {
-			if err := tx.Model(&profile).
-				Update("balance", gorm.Expr("balance + ?", booking.TotalPrice)).Error; err != nil {
-				return err
-			}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var bookings []models.Booking
+	if err := query.
+		Preload("Session.Film").
+		Preload("Session.Hall.Cinema").
+		Order("session.start_time DESC, booking.id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+
+	result := &dt.AdminBookingListDTO{
+		Items: make([]dt.AdminBookingDTO, 0, len(bookings)),
+		Page:  page,
+		Limit: limit,
+		Total: total,
+	}
+	for i := range bookings {
+		b := &bookings[i]
+		result.Items = append(result.Items, dt.AdminBookingDTO{
+			BookingDetailDTO: toBookingDetailDTO(b),
+			CustomerID:       b.CustomerID,
+			CanceledAt:       b.CanceledAt,
+			CanceledBy:       b.CanceledBy,
+			CancelReason:     b.CancelReason,
+		})
+	}
+
+	return result, nil
+}
+
+// Принудительно отменить бронирование клиента (с возвратом средств или без)
+func AdminCancelBooking(bookingID, adminID uint, input dt.AdminCancelBookingDTI) error {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return errors.New("необходимо указать причину отмены")
+	}
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+
+		var booking models.Booking
+		if err := tx.First(&booking, bookingID).Error; err != nil {
+			return errors.New("бронирование не найдено")
+		}
+		if booking.Status != models.BookingPaid && booking.Status != models.BookingReserved {
+			return errors.New("бронирование нельзя отменить")
+		}
+
+		// Администратор отменяет без учёта политики: полный возврат или без возврата
+		var quote *refundQuote
+		if input.Refund {
+			quote = fullRefundQuote(booking.TotalPrice, booking.SpendBonus, booking.ReceivedBonus)
+		}
+		if err := cancelBooking(tx, &booking, adminID, reason, quote); err != nil {
+			return err
+		}
+
+		// Место из заказа: если активных мест не осталось, закрываем и заказ
+		if booking.OrderID != nil {
+			return syncOrderStatus(tx, *booking.OrderID)
+		}
+
+		return nil
+	})
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// снять неоплаченную бронь, для которой не удалось создать платёж в шлюзе
+func releaseUnpaidBooking(bookingID, userID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var booking models.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, bookingID).Error; err != nil {
+			return err
+		}
+		if booking.Status != models.BookingReserved {
+			return nil
+		}
+		return cancelBooking(tx, &booking, userID, "не удалось создать платёж", nil)
+	})
+}
+
+// Снять все просроченные неоплаченные брони
+func ReleaseExpiredHolds() (int64, error) {
+	var released int64
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = releaseExpiredHolds(tx, nil)
+		return err
+	})
+	return released, err
+}
 
-			// Записываем возврат в историю оплат
-			payment := models.PaymentHistory{
-				UserID:    booking.CustomerID,
-				Amount:    booking.TotalPrice,
-				Operation: models.PaymentDeposit,
+// Фоновый процесс, периодически освобождающий просроченные брони
+func StartHoldSweeper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			released, err := ReleaseExpiredHolds()
+			if err != nil {
+				log.Printf("Ошибка при снятии просроченных броней: %v", err)
+				continue
 			}
-			if err := tx.Create(&payment).Error; err != nil {
-				return err
+			if released > 0 {
+				log.Printf("Снято просроченных броней: %d", released)
 			}
 		}
+	}()
+}
 
-		// Снимаем бонусы, которые начислялись за покупку
-		if booking.ReceivedBonus > 0 {
-			if profile.Bonus >= booking.ReceivedBonus {
-				// хватает бонусов → списываем бонусами
-				if err := tx.Model(&profile).
-					Update("bonus", gorm.Expr("bonus - ?", booking.ReceivedBonus)).Error; err != nil {
-					return err
-				}
-
-				bonus := models.BonusHistory{
-					UserID:    booking.CustomerID,
-					Amount:    booking.ReceivedBonus,
-					Operation: models.BonusRedeem,
-				}
-				if err := tx.Create(&bonus).Error; err != nil {
-					return err
-				}
-			} else {
-				// не хватает бонусов → остаток списываем с баланса
-				missing := booking.ReceivedBonus - profile.Bonus
-
-				if err := tx.Model(&profile).Updates(map[string]interface{}{
-					"bonus":   0, // все бонусы обнуляем
-					"balance": gorm.Expr("balance - ?", missing),
-				}).Error; err != nil {
-					return err
-				}
-
-				// История по бонусам
-				if profile.Bonus > 0 {
-					bonus := models.BonusHistory{
-						UserID:    booking.CustomerID,
-						Amount:    profile.Bonus,
-						Operation: models.BonusRedeem,
-					}
-					if err := tx.Create(&bonus).Error; err != nil {
-						return err
-					}
-				}
-
-				// История по балансу (добор недостающих бонусов)
-				payment := models.PaymentHistory{
-					UserID:    booking.CustomerID,
-					Amount:    missing,
-					Operation: models.PaymentSpend, // или отдельный тип, например PaymentAdjustment
-				}
-				if err := tx.Create(&payment).Error; err != nil {
-					return err
-				}
-			}
+// отмена брони: возврат средств по расчёту (nil — без возврата) и запись о том, кто, когда и почему отменил
+func cancelBooking(tx *gorm.DB, booking *models.Booking, canceledBy uint, reason string, quote *refundQuote) error {
+	var refunded money.Money
+	if booking.Status == models.BookingPaid && quote != nil {
+		profile, err := lockProfile(tx, booking.CustomerID)
+		if err != nil {
+			return err
 		}
 
-		// Обновляем статус брони
-		if err := tx.Model(&booking).
-			Update("status", models.BookingCanceled).Error; err != nil {
+		// Возвращаем деньги и потраченные бонусы, снимаем начисленные
+		if err := refundForBooking(tx, profile, booking.CustomerID, quote, models.LedgerEntry{
+			Description: fmt.Sprintf("Возврат за бронь #%d", booking.ID),
+			BookingID:   &booking.ID,
+		}); err != nil {
 			return err
 		}
+		refunded = quote.Money
+	}
+
+	now := time.Now()
+	return tx.Model(booking).Updates(map[string]interface{}{
+		"status":          models.BookingCanceled,
+		"expires_at":      nil,
+		"canceled_at":     now,
+		"canceled_by":     canceledBy,
+		"cancel_reason":   reason,
+		"refunded_amount": refunded,
+	}).Error
+}
 
+// если в заказе не осталось активных мест — заказ считается отменённым
+func syncOrderStatus(tx *gorm.DB, orderID uint) error {
+	var active int64
+	if err := tx.Model(&models.Booking{}).
+		Where("order_id = ? AND status IN ?", orderID, []models.BookingStatus{
+			models.BookingReserved,
+			models.BookingPaid,
+		}).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
 		return nil
-	})
+	}
+
+	return tx.Model(&models.Order{}).
+		Where("id = ?", orderID).
+		Update("status", models.BookingCanceled).Error
 }
 
-// ____________________________________________________INTERNAL____________________________________________________
-func isSeatTaken(tx *gorm.DB, sessionID, row, seat uint) (bool, error) {
-	var count int64
+// сколько процентов от цены брони начисляется бонусами, если бонусы не тратятся
+const bonusPercent = 10
 
-	err := tx.Model(&models.Booking{}).
-		Where("session_id = ? AND row_num = ? AND seat_num = ? AND status IN ?",
-			sessionID, row, seat, []models.BookingStatus{
-				models.BookingReserved,
-				models.BookingPaid,
-			}).
-		Count(&count).Error
+// расчёт стоимости брони с учётом бонусов
+func calcBookingPrice(price, bonus money.Money, useBonus bool) (spend, received, total money.Money) {
+	if useBonus {
+		if bonus > price {
+			return price, 0, 0
+		}
+		return bonus, 0, price - bonus
+	}
 
-	if err != nil {
-		return false, err
+	return 0, price.Percent(bonusPercent), price
+}
+
+// списание денег и бонусов за бронь или заказ одной проводкой: деньги с баланса в выручку,
+// потраченные бонусы погашаются, начисленные выпускаются. Не хватает денег или бонусов — ErrInsufficientFunds / ErrInsufficientBonus
+func chargeForBooking(tx *gorm.DB, userID uint, spend, received, total money.Money, entry models.LedgerEntry) error {
+	entry.Operation = models.LedgerBookingPayment
+	entry.UserID = userID
+	return post(tx, entry, append(bonusPostings(userID, spend, received),
+		posting{models.AccountWallet, userID, -total},
+		posting{models.AccountRevenue, 0, total},
+	)...)
+}
+
+// возврат по брони одной проводкой (профиль должен быть заблокирован):
+// деньги на баланс, потраченные бонусы обратно, начисленные бонусы забираются.
+// Если бонусов на счёте не хватает, остаток начисленных удерживается с баланса, а то,
+// что не покрыл и баланс, записывается в долг клиента — нехватка средств у одного клиента
+// не должна срывать отмену, в том числе отмену сеанса кинотеатром
+func refundForBooking(tx *gorm.DB, profile *models.Profile, userID uint, quote *refundQuote, entry models.LedgerEntry) error {
+	// Сначала возвращаем потраченные бонусы — ими же можно покрыть удержание
+	available := profile.Bonus + quote.BonusReturn
+	fromBonus := money.Min(quote.BonusClawback, available)
+	shortfall := quote.BonusClawback - fromBonus
+	fromWallet := money.Min(shortfall, profile.Balance+quote.Money)
+	debt := shortfall - fromWallet
+
+	entry.Operation = models.LedgerRefund
+	entry.UserID = userID
+	return post(tx, entry,
+		// деньги — из возвратов на баланс
+		posting{models.AccountRefunds, 0, -quote.Money},
+		posting{models.AccountWallet, userID, quote.Money},
+		// потраченные бонусы — обратно
+		posting{models.AccountBonusPool, 0, -quote.BonusReturn},
+		posting{models.AccountBonus, userID, quote.BonusReturn},
+		// начисленные бонусы — забираем
+		posting{models.AccountBonus, userID, -fromBonus},
+		posting{models.AccountBonusPool, 0, fromBonus},
+		// добор недостающих бонусов с баланса
+		posting{models.AccountWallet, userID, -fromWallet},
+		posting{models.AccountRevenue, 0, fromWallet},
+		// и в долг — то, что не покрыл баланс
+		posting{models.AccountDebt, userID, -debt},
+		posting{models.AccountRevenue, 0, debt},
+	)
+}
+
+// истекло ли время удержания неоплаченной брони
+func isHoldExpired(booking *models.Booking, now time.Time) bool {
+	return booking.Status == models.BookingReserved &&
+		booking.ExpiresAt != nil && !booking.ExpiresAt.After(now)
+}
+
+// снять истёкшие неоплаченные брони (все или только на место seat): место снова продаётся —
+// отменённая бронь не входит в индекс idx_seat_active, — а клиент получает уведомление
+func releaseExpiredHolds(tx *gorm.DB, seat *models.Booking) (int64, error) {
+	now := time.Now()
+	query := tx.Where("status = ? AND expires_at <= ?", models.BookingReserved, now)
+	if seat != nil {
+		query = query.Where("session_id = ? AND row_num = ? AND seat_num = ?", seat.SessionID, seat.RowNum, seat.SeatNum)
+	}
+
+	var released []models.Booking
+	if err := query.Model(&released).
+		Clauses(clause.Returning{}).
+		Updates(map[string]interface{}{
+			"status":        models.BookingCanceled,
+			"expires_at":    nil,
+			"canceled_at":   now,
+			"cancel_reason": "истёк срок оплаты",
+		}).Error; err != nil {
+		return 0, err
+	}
+	if len(released) == 0 {
+		return 0, nil
+	}
+
+	sessionIDs := make([]uint, 0, len(released))
+	for _, b := range released {
+		sessionIDs = append(sessionIDs, b.SessionID)
+	}
+	var sessions []models.Session
+	if err := tx.Preload("Film").Preload("Hall.Cinema").Where("id IN ?", sessionIDs).Find(&sessions).Error; err != nil {
+		return 0, err
+	}
+	byID := make(map[uint]*models.Session, len(sessions))
+	for i := range sessions {
+		byID[sessions[i].ID] = &sessions[i]
+	}
+
+	for _, b := range released {
+		title := "Бронь снята"
+		if s, ok := byID[b.SessionID]; ok {
+			title = fmt.Sprintf("Бронь на «%s» %s снята", s.Film.Title, s.StartTime.In(sessionLocation(s)).Format("02.01.2006 15:04"))
+		}
+		body := fmt.Sprintf("Ряд %d, место %d: истёк срок оплаты, место освобождено.", b.RowNum, b.SeatNum)
+		if err := enqueueNotification(tx, b.CustomerID, notifyHoldExpired, title, body); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(released)), nil
+}
+
+// занятые места: оплаченные и ещё не истёкшие неоплаченные брони
+func occupiedSeats(tx *gorm.DB) *gorm.DB {
+	return tx.Where("(booking.status = ? OR (booking.status = ? AND (booking.expires_at IS NULL OR booking.expires_at > ?)))",
+		models.BookingPaid, models.BookingReserved, time.Now())
+}
+
+// занимает место под бронь: снимает истёкшую бронь на это место и вставляет новую.
+// Одновременные попытки занять одно место разрешает уникальный индекс idx_seat_active
+// по активным броням: побеждает первая транзакция, остальные получают ErrSeatTaken.
+func reserveSeat(tx *gorm.DB, booking *models.Booking) error {
+	if _, err := releaseExpiredHolds(tx, booking); err != nil {
+		return err
+	}
+
+	if err := tx.Create(booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("%w: ряд %d, место %d", ErrSeatTaken, booking.RowNum, booking.SeatNum)
+		}
+		return err
 	}
 
-	return count > 0, nil
+	return nil
 }
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Получить историю своих броней с фильтром и пагинацией
+func GetMyBookings(userID uint, filter dt.BookingFilterDTI) (*dt.BookingListDTO, error) {
+	page, limit := normalizePage(filter.Page, filter.Limit)
+
+	query := db.DB.Model(&models.Booking{}).
+		Joins("JOIN session ON session.id = booking.session_id").
+		Where("booking.customer_id = ?", userID)
+
+	query, err := applyBookingStatusFilter(query, filter.Status)
+	if err != nil {
+		return nil, err
+	}
+	query = query.Session(&gorm.Session{}) // запрос переиспользуется для подсчёта и выборки
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var bookings []models.Booking
+	if err := query.
+		Preload("Session.Film").
+		Preload("Session.Hall.Cinema").
+		Order("session.start_time DESC, booking.id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+
+	result := &dt.BookingListDTO{
+		Items: make([]dt.BookingDetailDTO, 0, len(bookings)),
+		Page:  page,
+		Limit: limit,
+		Total: total,
+	}
+	for i := range bookings {
+		result.Items = append(result.Items, toBookingDetailDTO(&bookings[i]))
+	}
+
+	return result, nil
+}
+
+// Получить подробности своей брони (билет)
+func GetMyBooking(bookingID, userID uint) (*dt.BookingDetailDTO, error) {
+	var booking models.Booking
+	if err := db.DB.
+		Preload("Session.Film").
+		Preload("Session.Hall.Cinema").
+		First(&booking, bookingID).Error; err != nil {
+		return nil, errors.New("бронирование не найдено")
+	}
+	if booking.CustomerID != userID {
+		return nil, errors.New("нельзя просматривать чужое бронирование")
+	}
+
+	result := toBookingDetailDTO(&booking)
+	return &result, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// фильтр броней по состоянию: предстоящие, прошедшие, отменённые
+func applyBookingStatusFilter(query *gorm.DB, status string) (*gorm.DB, error) {
+	now := time.Now()
+
+	switch status {
+	case "":
+		return query, nil
+	case "upcoming":
+		// истёкшее удержание ещё может лежать в статусе reserved до очистки, но место уже не держит
+		return query.Where("(booking.status = ? OR (booking.status = ? AND (booking.expires_at IS NULL OR booking.expires_at > ?))) AND session.start_time >= ?",
+			models.BookingPaid, models.BookingReserved, now, now), nil
+	case "past":
+		return query.Where("booking.status = ? AND session.start_time < ?", models.BookingPaid, now), nil
+	case "cancelled", "canceled":
+		return query.Where("booking.status = ?", models.BookingCanceled), nil
+	default:
+		return nil, errors.New("неизвестный фильтр статуса")
+	}
+}
+
+// собирает подробный ответ по брони (сеанс, фильм, зал и кинотеатр должны быть подгружены)
+func toBookingDetailDTO(b *models.Booking) dt.BookingDetailDTO {
+	return dt.BookingDetailDTO{
+		ID:             b.ID,
+		OrderID:        b.OrderID,
+		SessionID:      b.SessionID,
+		FilmID:         b.Session.FilmID,
+		FilmTitle:      b.Session.Film.Title,
+		HallID:         b.Session.HallID,
+		HallName:       b.Session.Hall.Name,
+		CinemaID:       b.Session.Hall.CinemaID,
+		CinemaName:     b.Session.Hall.Cinema.Name,
+		StartTime:      b.Session.StartTime.UTC(),
+		LocalStartTime: b.Session.StartTime.In(sessionLocation(&b.Session)),
+		RowNum:         b.RowNum,
+		SeatNum:        b.SeatNum,
+		SpendBonus:     b.SpendBonus,
+		ReceivedBonus:  b.ReceivedBonus,
+		TotalPrice:     b.TotalPrice,
+		RefundedAmount: b.RefundedAmount,
+		Status:         b.Status,
+		ExpiresAt:      b.ExpiresAt,
+		PaymentMethod:  b.PaymentMethod,
+		CreatedAt:      b.CreatedAt,
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// способ занять место в гонке за одно и то же место
+type seatAttempt int
+
+const (
+	attemptBooking seatAttempt = iota // покупка с баланса
+	attemptHold                       // удержание без оплаты
+	attemptOrder                      // заказ из одного места
+)
+
+// кто занял место: бронь или заказ, чтобы потом отменить
+type seatWinner struct {
+	kind      seatAttempt
+	userID    uint
+	bookingID uint
+	orderID   uint
+}
+
+// Много одновременных попыток занять одно место через CreateBooking, HoldSeat и CreateOrder:
+// ровно один победитель, остальные получают ErrSeatTaken; после отмены место снова продаётся
+func TestSeatAllocationUnderConcurrency(t *testing.T) {
+	openTestDB(t)
+
+	const (
+		workers = 30
+		row     = 1
+		seat    = 1
+	)
+	price := money.FromMinor(300_00)
+	sessionID := newTestSession(t, 2, 5, price)
+
+	users := make([]uint, workers)
+	for i := range users {
+		users[i] = newTestUser(t, price*2)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		winners []seatWinner
+		failed  []error
+		taken   int
+		start   = make(chan struct{})
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(kind seatAttempt, userID uint) {
+			defer wg.Done()
+			<-start
+
+			winner := seatWinner{kind: kind, userID: userID}
+			var err error
+			switch kind {
+			case attemptBooking:
+				booking, e := CreateBooking(dt.CreateBookingDTI{UserID: userID, SessionID: sessionID, RowNum: row, SeatNum: seat})
+				if err = e; err == nil {
+					winner.bookingID = booking.ID
+				}
+			case attemptHold:
+				booking, e := HoldSeat(dt.HoldSeatDTI{UserID: userID, SessionID: sessionID, RowNum: row, SeatNum: seat})
+				if err = e; err == nil {
+					winner.bookingID = booking.ID
+				}
+			case attemptOrder:
+				order, e := CreateOrder(dt.CreateOrderDTI{
+					UserID:    userID,
+					SessionID: sessionID,
+					Seats:     []dt.SeatDTI{{RowNum: row, SeatNum: seat}},
+				})
+				if err = e; err == nil {
+					winner.orderID = order.ID
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				winners = append(winners, winner)
+			case errors.Is(err, ErrSeatTaken):
+				taken++
+			default:
+				failed = append(failed, err)
+			}
+		}(seatAttempt(i%3), users[i])
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range failed {
+		t.Errorf("ожидалась ErrSeatTaken, получено: %v", err)
+	}
+	if len(winners) != 1 {
+		t.Fatalf("место заняли %d раз, ожидался ровно один победитель", len(winners))
+	}
+	if taken != workers-1 {
+		t.Errorf("ErrSeatTaken получили %d попыток, ожидалось %d", taken, workers-1)
+	}
+
+	// После отмены место снова свободно
+	w := winners[0]
+	var err error
+	if w.kind == attemptOrder {
+		err = CancelOrder(w.orderID, w.userID)
+	} else {
+		err = CancelBooking(w.bookingID, w.userID)
+	}
+	if err != nil {
+		t.Fatalf("отмена победителя: %v", err)
+	}
+
+	again, other := users[0], users[1]
+	booking, err := CreateBooking(dt.CreateBookingDTI{UserID: again, SessionID: sessionID, RowNum: row, SeatNum: seat})
+	if err != nil {
+		t.Fatalf("место после отмены не продаётся: %v", err)
+	}
+	if _, err := HoldSeat(dt.HoldSeatDTI{UserID: other, SessionID: sessionID, RowNum: row, SeatNum: seat}); !errors.Is(err, ErrSeatTaken) {
+		t.Errorf("повторно проданное место удержано ещё раз: %v", err)
+	}
+	if err := CancelBooking(booking.ID, again); err != nil {
+		t.Errorf("отмена повторной покупки: %v", err)
+	}
+}
+
+// Истёкшая бронь снимается и при очистке, и при покупке того же места:
+// в обоих случаях у брони остаётся причина отмены, а клиенту уходит уведомление
+func TestExpiredHoldRelease(t *testing.T) {
+	openTestDB(t)
+
+	sessionID := newTestSession(t, 1, 2, 300_00)
+	owner, buyer := newTestUser(t, 0), newTestUser(t, 1000_00)
+
+	expire := func(bookingID uint) {
+		t.Helper()
+		if err := db.DB.Model(&models.Booking{}).Where("id = ?", bookingID).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkReleased := func(bookingID uint) {
+		t.Helper()
+		var booking models.Booking
+		if err := db.DB.First(&booking, bookingID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if booking.Status != models.BookingCanceled || booking.CanceledAt == nil || booking.CancelReason == "" {
+			t.Errorf("бронь #%d не снята как истёкшая: %+v", bookingID, booking)
+		}
+	}
+
+	// снятие при покупке того же места
+	hold, err := HoldSeat(dt.HoldSeatDTI{UserID: owner, SessionID: sessionID, RowNum: 1, SeatNum: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expire(hold.ID)
+	if _, err := CreateBooking(dt.CreateBookingDTI{UserID: buyer, SessionID: sessionID, RowNum: 1, SeatNum: 1}); err != nil {
+		t.Fatalf("место истёкшей брони не продаётся: %v", err)
+	}
+	checkReleased(hold.ID)
+
+	// снятие фоновой очисткой
+	hold, err = HoldSeat(dt.HoldSeatDTI{UserID: owner, SessionID: sessionID, RowNum: 1, SeatNum: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expire(hold.ID)
+	if _, err := ReleaseExpiredHolds(); err != nil {
+		t.Fatal(err)
+	}
+	checkReleased(hold.ID)
+
+	var notified int64
+	if err := db.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND kind = ?", owner, notifyHoldExpired).
+		Count(&notified).Error; err != nil {
+		t.Fatal(err)
+	}
+	if notified != 2 {
+		t.Errorf("уведомлений о снятии брони: %d, ожидалось 2", notified)
+	}
+}
+
+// Удержание начисленных бонусов, которое не покрывают ни бонусы, ни баланс, не срывает отмену:
+// баланс уходит в ноль, а остаток записывается в долг клиента
+func TestRefundClawbackShortfallBecomesDebt(t *testing.T) {
+	openTestDB(t)
+
+	userID := newTestUser(t, 30_00)
+	quote := &refundQuote{Percent: 100, Money: 20_00, BonusClawback: 80_00, PolicyName: defaultPolicyName}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		profile, err := lockProfile(tx, userID)
+		if err != nil {
+			return err
+		}
+		return refundForBooking(tx, profile, userID, quote, models.LedgerEntry{Description: "Тестовый возврат"})
+	})
+	if err != nil {
+		t.Fatalf("возврат с нехваткой средств: %v", err)
+	}
+
+	balance, err := GetBalance(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 0 {
+		t.Errorf("баланс %s, ожидался 0", balance)
+	}
+	debt, err := ledgerBalance(db.DB, models.AccountDebt, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if debt != -30_00 {
+		t.Errorf("долг %s, ожидалось -30.00", debt)
+	}
+}
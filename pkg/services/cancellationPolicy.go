@@ -0,0 +1,275 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// политика по умолчанию, если не задано ни одной подходящей: полный возврат до начала сеанса
+const defaultPolicyName = "Полный возврат до начала сеанса"
+
+// результат расчёта возврата по политике отмены
+type refundQuote struct {
+	Percent       uint
+	Money         money.Money // сколько денег вернуть на баланс
+	BonusReturn   money.Money // сколько потраченных на оплату бонусов вернуть
+	BonusClawback money.Money // сколько начисленных бонусов забрать
+	PolicyID      *uint
+	PolicyName    string
+}
+
+// Предпросмотр возврата при отмене своей брони
+func PreviewBookingRefund(bookingID, userID uint) (*dt.RefundPreviewDTO, error) {
+	var booking models.Booking
+	if err := db.DB.First(&booking, bookingID).Error; err != nil {
+		return nil, errors.New("бронирование не найдено")
+	}
+	if booking.CustomerID != userID {
+		return nil, errors.New("нельзя просматривать чужое бронирование")
+	}
+	if booking.Status != models.BookingPaid {
+		return nil, errors.New("бронирование нельзя отменить")
+	}
+
+	quote, err := quoteRefund(db.DB, booking.SessionID, booking.TotalPrice, booking.SpendBonus, booking.ReceivedBonus, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return quote.toDTO(), nil
+}
+
+// Предпросмотр возврата при отмене своего заказа
+func PreviewOrderRefund(orderID, userID uint) (*dt.RefundPreviewDTO, error) {
+	var order models.Order
+	if err := db.DB.First(&order, orderID).Error; err != nil {
+		return nil, errors.New("заказ не найден")
+	}
+	if order.CustomerID != userID {
+		return nil, errors.New("нельзя просматривать чужой заказ")
+	}
+	if order.Status != models.BookingPaid {
+		return nil, errors.New("заказ нельзя отменить")
+	}
+
+	rest, err := loadOrderRemainder(db.DB, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := quoteRefund(db.DB, order.SessionID, rest.Total, rest.Spend, rest.Received, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return quote.toDTO(), nil
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Получить все политики отмены
+func GetCancellationPolicies() ([]models.CancellationPolicy, error) {
+	var policies []models.CancellationPolicy
+	if err := db.DB.Preload("Rules", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("min_hours_before DESC")
+	}).Where("deleted_at IS NULL").Order("id ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// Создать политику отмены
+func CreateCancellationPolicy(input dt.CreateCancellationPolicyDTI) (*dt.CreateCancellationPolicyDTO, error) {
+	seen := make(map[float64]bool)
+	for _, r := range input.Rules {
+		if r.RefundPercent > 100 {
+			return nil, errors.New("процент возврата должен быть от 0 до 100")
+		}
+		if seen[r.MinHoursBefore] {
+			return nil, errors.New("правила политики не должны повторять один и тот же срок")
+		}
+		seen[r.MinHoursBefore] = true
+	}
+
+	policy := models.CancellationPolicy{
+		Name:       input.Name,
+		CinemaID:   input.CinemaID,
+		HallTypeID: input.HallTypeID,
+	}
+	for _, r := range input.Rules {
+		policy.Rules = append(policy.Rules, models.CancellationRule{
+			MinHoursBefore: r.MinHoursBefore,
+			RefundPercent:  r.RefundPercent,
+		})
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if input.CinemaID != nil {
+			var cinema models.Cinema
+			if err := tx.Where("deleted_at IS NULL").First(&cinema, *input.CinemaID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("кинотеатр не найден")
+				}
+				return err
+			}
+		}
+		if input.HallTypeID != nil {
+			var hallType models.HallType
+			if err := tx.Where("deleted_at IS NULL").First(&hallType, *input.HallTypeID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("тип зала не найден")
+				}
+				return err
+			}
+		}
+
+		// на одну область (кинотеатр, тип зала) — одна действующая политика,
+		// иначе выбор между ними зависел бы только от порядка создания
+		var count int64
+		if err := scopeQuery(tx.Model(&models.CancellationPolicy{}), input.CinemaID, input.HallTypeID).
+			Where("deleted_at IS NULL").
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("политика для этого кинотеатра и типа зала уже существует")
+		}
+
+		return tx.Create(&policy).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dt.CreateCancellationPolicyDTO{ID: policy.ID}, nil
+}
+
+// Удалить политику отмены (мягкое удаление: правила остаются для истории возвратов)
+func DeleteCancellationPolicy(id uint) error {
+	res := db.DB.Model(&models.CancellationPolicy{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// расчёт возврата по политике, действующей для зала сеанса
+func quoteRefund(tx *gorm.DB, sessionID uint, total, spend, received money.Money, now time.Time) (*refundQuote, error) {
+	var session models.Session
+	if err := tx.Preload("Hall").First(&session, sessionID).Error; err != nil {
+		return nil, errors.New("сеанс не найден")
+	}
+	if !now.Before(session.StartTime) {
+		return nil, errors.New("сеанс уже начался, отмена невозможна")
+	}
+
+	policy, err := findCancellationPolicy(tx, session.Hall.CinemaID, session.Hall.HallTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &refundQuote{Percent: 100, PolicyName: defaultPolicyName}
+	if policy != nil {
+		quote.PolicyID = &policy.ID
+		quote.PolicyName = policy.Name
+		quote.Percent = refundPercent(policy.Rules, session.StartTime.Sub(now).Hours())
+	}
+
+	quote.Money = total.Percent(quote.Percent)
+	quote.BonusReturn = spend.Percent(quote.Percent)
+	quote.BonusClawback = received.Percent(quote.Percent)
+
+	return quote, nil
+}
+
+// полный возврат без учёта политики (отмена администратором)
+func fullRefundQuote(total, spend, received money.Money) *refundQuote {
+	return &refundQuote{
+		Percent:       100,
+		Money:         total,
+		BonusReturn:   spend,
+		BonusClawback: received,
+		PolicyName:    "Отмена администратором",
+	}
+}
+
+// самая точная политика для зала: кинотеатр и тип зала > кинотеатр > тип зала > общая
+func findCancellationPolicy(tx *gorm.DB, cinemaID, hallTypeID uint) (*models.CancellationPolicy, error) {
+	var policies []models.CancellationPolicy
+	if err := tx.Preload("Rules").
+		Where("(cinema_id = ? OR cinema_id IS NULL) AND (hall_type_id = ? OR hall_type_id IS NULL)", cinemaID, hallTypeID).
+		Where("deleted_at IS NULL").
+		Order("id DESC").
+		Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	var best *models.CancellationPolicy
+	bestScore := -1
+	for i := range policies {
+		score := 0
+		if policies[i].CinemaID != nil {
+			score += 2
+		}
+		if policies[i].HallTypeID != nil {
+			score++
+		}
+		if score > bestScore {
+			best = &policies[i]
+			bestScore = score
+		}
+	}
+
+	return best, nil
+}
+
+// условие на область политики; отсутствующий кинотеатр или тип зала означает «любой»
+func scopeQuery(tx *gorm.DB, cinemaID, hallTypeID *uint) *gorm.DB {
+	if cinemaID != nil {
+		tx = tx.Where("cinema_id = ?", *cinemaID)
+	} else {
+		tx = tx.Where("cinema_id IS NULL")
+	}
+	if hallTypeID != nil {
+		tx = tx.Where("hall_type_id = ?", *hallTypeID)
+	} else {
+		tx = tx.Where("hall_type_id IS NULL")
+	}
+	return tx
+}
+
+// процент возврата: правило с наибольшим сроком, который ещё не прошёл; иначе 0
+func refundPercent(rules []models.CancellationRule, hoursLeft float64) uint {
+	sorted := make([]models.CancellationRule, len(rules))
+	copy(sorted, rules)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinHoursBefore > sorted[j].MinHoursBefore
+	})
+
+	for _, r := range sorted {
+		if hoursLeft >= r.MinHoursBefore {
+			return r.RefundPercent
+		}
+	}
+	return 0
+}
+
+func (q *refundQuote) toDTO() *dt.RefundPreviewDTO {
+	return &dt.RefundPreviewDTO{
+		RefundPercent: q.Percent,
+		RefundAmount:  q.Money,
+		BonusReturn:   q.BonusReturn,
+		BonusClawback: q.BonusClawback,
+		PolicyID:      q.PolicyID,
+		PolicyName:    q.PolicyName,
+	}
+}
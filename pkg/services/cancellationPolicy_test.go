@@ -0,0 +1,198 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// правила намеренно не по порядку: refundPercent сортирует их сама
+var testRefundRules = []models.CancellationRule{
+	{MinHoursBefore: 3, RefundPercent: 50},
+	{MinHoursBefore: 72, RefundPercent: 100},
+	{MinHoursBefore: 24, RefundPercent: 80},
+}
+
+func TestRefundPercent(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []models.CancellationRule
+		hoursLeft float64
+		want      uint
+	}{
+		{"задолго до сеанса", testRefundRules, 200, 100},
+		{"ровно на границе 72 ч", testRefundRules, 72, 100},
+		{"чуть меньше 72 ч", testRefundRules, 71.99, 80},
+		{"ровно на границе 24 ч", testRefundRules, 24, 80},
+		{"чуть меньше 24 ч", testRefundRules, 23.99, 50},
+		{"ровно на границе 3 ч", testRefundRules, 3, 50},
+		{"чуть меньше 3 ч", testRefundRules, 2.99, 0},
+		{"перед самым началом", testRefundRules, 0, 0},
+
+		{"правило с нуля часов", []models.CancellationRule{{MinHoursBefore: 0, RefundPercent: 10}}, 0, 10},
+		{"правило с нуля часов после начала", []models.CancellationRule{{MinHoursBefore: 0, RefundPercent: 10}}, -0.5, 0},
+		{"дробная граница", []models.CancellationRule{{MinHoursBefore: 1.5, RefundPercent: 30}}, 1.5, 30},
+		{"дробная граница не достигнута", []models.CancellationRule{{MinHoursBefore: 1.5, RefundPercent: 30}}, 1.49, 0},
+		{"без правил", nil, 100, 0},
+	}
+	for _, tt := range tests {
+		if got := refundPercent(tt.rules, tt.hoursLeft); got != tt.want {
+			t.Errorf("%s: за %.2f ч возврат %d%%, ожидалось %d%%", tt.name, tt.hoursLeft, got, tt.want)
+		}
+	}
+
+	// исходный порядок правил политики не меняется
+	if testRefundRules[0].MinHoursBefore != 3 || testRefundRules[1].MinHoursBefore != 72 {
+		t.Errorf("refundPercent переставила правила политики: %+v", testRefundRules)
+	}
+}
+
+func TestQuoteRefund(t *testing.T) {
+	openTestDB(t)
+
+	sessionID := newTestSession(t, 1, 5, money.FromMinor(333_33))
+	var session models.Session
+	if err := db.DB.Preload("Hall").First(&session, sessionID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Всё остальное — в транзакции теста. Кинотеатр и тип зала новые, поэтому на них действуют
+	// только общие политики (без кинотеатра и типа зала); в транзакции их убираем
+	tx := testTx(t)
+	if err := tx.Where("cinema_id IS NULL AND hall_type_id IS NULL").
+		Delete(&models.CancellationPolicy{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// без политики — полный возврат
+	quote, err := quoteRefund(tx, sessionID, 100_00, 20_00, 10_00, session.StartTime.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.Percent != 100 || quote.PolicyID != nil || quote.Money != 100_00 ||
+		quote.BonusReturn != 20_00 || quote.BonusClawback != 10_00 {
+		t.Errorf("без политики: %+v", quote)
+	}
+
+	cinemaID, hallTypeID := session.Hall.CinemaID, session.Hall.HallTypeID
+	policy := models.CancellationPolicy{
+		Name:       "Тестовая",
+		CinemaID:   &cinemaID,
+		HallTypeID: &hallTypeID,
+		Rules:      append([]models.CancellationRule(nil), testRefundRules...),
+	}
+	if err := tx.Create(&policy).Error; err != nil {
+		t.Fatalf("политика: %v", err)
+	}
+
+	const (
+		total    money.Money = 333_33
+		spend    money.Money = 50_00
+		received money.Money = 16_67
+	)
+	tests := []struct {
+		before  time.Duration // сколько осталось до начала сеанса
+		percent uint
+		refund  money.Money
+		bonus   money.Money
+		claw    money.Money
+	}{
+		{72 * time.Hour, 100, 333_33, 50_00, 16_67},
+		{72*time.Hour - time.Second, 80, 266_66, 40_00, 13_34},
+		{24 * time.Hour, 80, 266_66, 40_00, 13_34},
+		{24*time.Hour - time.Second, 50, 166_67, 25_00, 8_34},
+		{3 * time.Hour, 50, 166_67, 25_00, 8_34},
+		{3*time.Hour - time.Second, 0, 0, 0, 0},
+		{time.Second, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		quote, err := quoteRefund(tx, sessionID, total, spend, received, session.StartTime.Add(-tt.before))
+		if err != nil {
+			t.Errorf("за %s: %v", tt.before, err)
+			continue
+		}
+		if quote.PolicyID == nil || *quote.PolicyID != policy.ID {
+			t.Errorf("за %s: выбрана политика %v, ожидалась %d", tt.before, quote.PolicyID, policy.ID)
+		}
+		if quote.Percent != tt.percent || quote.Money != tt.refund ||
+			quote.BonusReturn != tt.bonus || quote.BonusClawback != tt.claw {
+			t.Errorf("за %s: %d%%, деньги %s, бонусы %s, списание %s; ожидалось %d%%, %s, %s, %s",
+				tt.before, quote.Percent, quote.Money, quote.BonusReturn, quote.BonusClawback,
+				tt.percent, tt.refund, tt.bonus, tt.claw)
+		}
+	}
+
+	// после начала сеанса отменить нельзя
+	for _, after := range []time.Duration{0, time.Minute} {
+		if _, err := quoteRefund(tx, sessionID, total, spend, received, session.StartTime.Add(after)); err == nil {
+			t.Errorf("отмена через %s после начала сеанса разрешена", after)
+		}
+	}
+}
+
+func TestCreateCancellationPolicyScope(t *testing.T) {
+	openTestDB(t)
+
+	sessionID := newTestSession(t, 1, 1, 100_00)
+	var session models.Session
+	if err := db.DB.Preload("Hall").First(&session, sessionID).Error; err != nil {
+		t.Fatal(err)
+	}
+	cinemaID, hallTypeID := session.Hall.CinemaID, session.Hall.HallTypeID
+	missing := uint(math.MaxInt32)
+
+	input := func(cinema, hallType *uint) dt.CreateCancellationPolicyDTI {
+		return dt.CreateCancellationPolicyDTI{
+			Name:       "Тестовая",
+			CinemaID:   cinema,
+			HallTypeID: hallType,
+			Rules:      []dt.CancellationRuleDTI{{MinHoursBefore: 0, RefundPercent: 50}},
+		}
+	}
+
+	// ссылки на несуществующие кинотеатр и тип зала — ошибка ввода, а не ошибка внешнего ключа
+	if _, err := CreateCancellationPolicy(input(&missing, nil)); err == nil || err.Error() != "кинотеатр не найден" {
+		t.Errorf("несуществующий кинотеатр: %v", err)
+	}
+	if _, err := CreateCancellationPolicy(input(nil, &missing)); err == nil || err.Error() != "тип зала не найден" {
+		t.Errorf("несуществующий тип зала: %v", err)
+	}
+
+	created, err := CreateCancellationPolicy(input(&cinemaID, &hallTypeID))
+	if err != nil {
+		t.Fatalf("политика: %v", err)
+	}
+	if _, err := CreateCancellationPolicy(input(&cinemaID, &hallTypeID)); err == nil {
+		t.Error("вторая политика для той же области создана")
+	}
+	// другая область того же кинотеатра — отдельная политика
+	other, err := CreateCancellationPolicy(input(&cinemaID, nil))
+	if err != nil {
+		t.Fatalf("политика кинотеатра: %v", err)
+	}
+
+	// удалённая политика не действует и не мешает создать новую для той же области
+	if err := DeleteCancellationPolicy(created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteCancellationPolicy(created.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("повторное удаление: %v", err)
+	}
+	policy, err := findCancellationPolicy(db.DB, cinemaID, hallTypeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy == nil || policy.ID != other.ID {
+		t.Errorf("после удаления выбрана политика %+v, ожидалась %d", policy, other.ID)
+	}
+	if _, err := CreateCancellationPolicy(input(&cinemaID, &hallTypeID)); err != nil {
+		t.Errorf("политика после удаления прежней: %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Получить все кинотеатры
+func GetCinemas() ([]models.Cinema, error) {
+	var cinemas []models.Cinema
+	if err := db.DB.Where("deleted_at IS NULL").Order("name ASC").Find(&cinemas).Error; err != nil {
+		return nil, err
+	}
+	return cinemas, nil
+}
+
+// Получить кинотеатр с его залами
+func GetCinema(id uint) (*dt.CinemaDTO, error) {
+	var cinema models.Cinema
+	if err := db.DB.Where("deleted_at IS NULL").First(&cinema, id).Error; err != nil {
+		return nil, errors.New("кинотеатр не найден")
+	}
+
+	var halls []models.CinemaHall
+	if err := db.DB.Preload("HallType").
+		Where("cinema_id = ? AND deleted_at IS NULL", id).
+		Order("name ASC").
+		Find(&halls).Error; err != nil {
+		return nil, err
+	}
+
+	result := toCinemaDTO(&cinema)
+	for _, h := range halls {
+		result.Halls = append(result.Halls, dt.CinemaHallDTO{
+			ID:       h.ID,
+			Name:     h.Name,
+			HallType: h.HallType.Name,
+			Capacity: h.Capacity,
+		})
+	}
+	return &result, nil
+}
+
+// Расписание кинотеатра на день (YYYY-MM-DD, по умолчанию сегодня), сгруппированное по фильмам и залам.
+// День считается по часовому поясу кинотеатра. На сегодня показываются только сеансы, которые ещё не начались
+func GetCinemaSchedule(cinemaID uint, date string) (*dt.CinemaScheduleDTO, error) {
+	var cinema models.Cinema
+	if err := db.DB.Where("deleted_at IS NULL").First(&cinema, cinemaID).Error; err != nil {
+		return nil, errors.New("кинотеатр не найден")
+	}
+
+	loc := cinemaLocation(&cinema)
+	now := time.Now()
+	day := startOfDay(now, loc)
+	if date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			return nil, errors.New("неверный формат даты, ожидается YYYY-MM-DD")
+		}
+		day = parsed
+	}
+
+	from := day
+	if now.After(from) {
+		from = now
+	}
+
+	var sessions []models.Session
+	if err := db.DB.Preload("Film").Preload("Hall.HallType").
+		Joins("JOIN cinema_hall ON cinema_hall.id = session.hall_id").
+		Where("cinema_hall.cinema_id = ? AND session.deleted_at IS NULL", cinemaID).
+		Where("session.status <> ?", models.SessionCanceled).
+		Where("session.start_time >= ? AND session.start_time < ?", from, day.AddDate(0, 0, 1)).
+		Order("session.start_time ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return &dt.CinemaScheduleDTO{
+		CinemaID:   cinema.ID,
+		CinemaName: cinema.Name,
+		Timezone:   loc.String(),
+		Date:       day.Format("2006-01-02"),
+		Films:      groupSchedule(sessions, loc),
+	}, nil
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Создать кинотеатр
+func CreateCinema(input dt.CreateCinemaDTI) (*dt.CreateCinemaDTO, error) {
+	loc, err := loadLocation(input.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	cinema := models.Cinema{
+		Name:     input.Name,
+		Location: input.Location,
+		Phone:    input.Phone,
+		Email:    input.Email,
+		Timezone: loc.String(),
+		AgeCheck: models.AgeCheckBlock,
+	}
+	if input.AgeCheck != "" {
+		if cinema.AgeCheck, err = parseAgeCheck(input.AgeCheck); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := db.DB.Create(&cinema).Error; err != nil {
+		return nil, err
+	}
+
+	return &dt.CreateCinemaDTO{ID: cinema.ID}, nil
+}
+
+// Обновить кинотеатр (частично)
+func UpdateCinema(id uint, updates map[string]interface{}) error {
+	filtered := FilterUpdates(onlyFields(updates, "name", "location", "phone", "email", "timezone", "age_check"))
+	if len(filtered) == 0 {
+		return errors.New("пустой запрос")
+	}
+	// смена пояса не сдвигает сеансы: они хранятся в UTC, меняется только местное время в выдаче
+	if v, ok := filtered["timezone"]; ok {
+		name, _ := v.(string)
+		loc, err := loadLocation(name)
+		if err != nil {
+			return err
+		}
+		filtered["timezone"] = loc.String()
+	}
+	if v, ok := filtered["age_check"]; ok {
+		mode, err := parseAgeCheck(fmt.Sprint(v))
+		if err != nil {
+			return err
+		}
+		filtered["age_check"] = mode
+	}
+
+	res := db.DB.Model(&models.Cinema{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(filtered)
+	if res.Error != nil {
+		return errors.New("ошибка при обновлении кинотеатра")
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("кинотеатр не найден")
+	}
+	return nil
+}
+
+// Удалить кинотеатр. Удалить можно только кинотеатр без действующих залов;
+// запись помечается удалённой, чтобы не терять историю сеансов в архивных залах
+func DeleteCinema(id uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var halls int64
+		if err := tx.Model(&models.CinemaHall{}).
+			Where("cinema_id = ? AND deleted_at IS NULL", id).
+			Count(&halls).Error; err != nil {
+			return err
+		}
+		if halls > 0 {
+			return fmt.Errorf("%w: в кинотеатре есть залы", ErrInUse)
+		}
+
+		res := tx.Model(&models.Cinema{}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Update("deleted_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// группирует сеансы по фильмам и залам; фильмы по названию, залы по имени, сеансы по времени
+func groupSchedule(sessions []models.Session, loc *time.Location) []dt.ScheduleFilmDTO {
+	films := make([]dt.ScheduleFilmDTO, 0)
+	filmIdx := make(map[uint]int)
+	hallIdx := make(map[[2]uint]int)
+
+	for _, s := range sessions {
+		fi, ok := filmIdx[s.FilmID]
+		if !ok {
+			films = append(films, dt.ScheduleFilmDTO{
+				FilmID:    s.Film.ID,
+				Title:     s.Film.Title,
+				Duration:  s.Film.Duration,
+				AgeRating: s.Film.AgeRating,
+			})
+			fi = len(films) - 1
+			filmIdx[s.FilmID] = fi
+		}
+
+		key := [2]uint{s.FilmID, s.HallID}
+		hi, ok := hallIdx[key]
+		if !ok {
+			films[fi].Halls = append(films[fi].Halls, dt.ScheduleHallDTO{
+				HallID:   s.Hall.ID,
+				HallName: s.Hall.Name,
+				HallType: s.Hall.HallType.Name,
+			})
+			hi = len(films[fi].Halls) - 1
+			hallIdx[key] = hi
+		}
+
+		films[fi].Halls[hi].Sessions = append(films[fi].Halls[hi].Sessions, dt.ScheduleSessionDTO{
+			ID:             s.ID,
+			StartTime:      s.StartTime.UTC(),
+			LocalStartTime: s.StartTime.In(loc),
+			Price:          s.Price,
+			Surcharge:      s.Surcharge,
+
+			SessionAttributesDTO: toSessionAttributesDTO(s.SessionAttributes),
+		})
+	}
+
+	sort.SliceStable(films, func(i, j int) bool {
+		return films[i].Title < films[j].Title
+	})
+	for i := range films {
+		halls := films[i].Halls
+		sort.SliceStable(halls, func(a, b int) bool {
+			return halls[a].HallName < halls[b].HallName
+		})
+	}
+
+	return films
+}
+
+func toCinemaDTO(c *models.Cinema) dt.CinemaDTO {
+	return dt.CinemaDTO{
+		ID:       c.ID,
+		Name:     c.Name,
+		Location: c.Location,
+		Phone:    c.Phone,
+		Email:    c.Email,
+		Timezone: c.Timezone,
+		AgeCheck: string(c.AgeCheck),
+	}
+}
+
+// режим проверки возраста кинотеатра из запроса администратора
+func parseAgeCheck(raw string) (models.AgeCheckMode, error) {
+	mode := models.AgeCheckMode(raw)
+	if mode != models.AgeCheckBlock && mode != models.AgeCheckWarn {
+		return "", fmt.Errorf("%w: age_check ожидается block или warn", ErrInvalidCinema)
+	}
+	return mode, nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"CinemaBooking/pkg/models"
+	"errors"
+	"testing"
+)
+
+func TestParseAgeCheck(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want models.AgeCheckMode
+		ok   bool
+	}{
+		{"block", models.AgeCheckBlock, true},
+		{"warn", models.AgeCheckWarn, true},
+		{"", "", false},
+		{"Block", "", false},
+		{"off", "", false},
+	}
+	for _, tt := range tests {
+		got, err := parseAgeCheck(tt.raw)
+		if !tt.ok {
+			if !errors.Is(err, ErrInvalidCinema) {
+				t.Errorf("%q: ожидалась ErrInvalidCinema, получено %q, %v", tt.raw, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: %q, %v; ожидалось %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
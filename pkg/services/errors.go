@@ -0,0 +1,23 @@
+package services
+
+import "errors"
+
+// Ошибки, которые обработчики сопоставляют с отдельными HTTP-статусами
+var (
+	ErrInsufficientFunds = errors.New("недостаточно средств на балансе")
+	ErrInsufficientBonus = errors.New("недостаточно бонусов")
+	ErrSeatTaken         = errors.New("место занято")
+	ErrInvalidSeat       = errors.New("такого места нет в зале")
+	ErrSessionStarted    = errors.New("сеанс уже начался")
+	ErrSessionCanceled   = errors.New("сеанс отменён")
+	ErrInvalidLayout     = errors.New("некорректная схема зала")
+	ErrHallInUse         = errors.New("в зале есть предстоящие сеансы")
+	ErrInUse             = errors.New("запись используется")
+	ErrInvalidPrice      = errors.New("некорректные цены сеанса")
+	ErrInvalidSession    = errors.New("некорректные данные сеанса")
+	ErrInvalidTimezone   = errors.New("неизвестный часовой пояс")
+	ErrAgeRestricted     = errors.New("возрастное ограничение")
+	ErrInvalidCinema     = errors.New("некорректные данные кинотеатра")
+	ErrPaymentGateway    = errors.New("ошибка платёжного шлюза")
+	ErrWebhookDisabled   = errors.New("приём уведомлений шлюза не настроен")
+)
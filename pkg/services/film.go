@@ -18,11 +18,24 @@ func GetFilm(id uint) (*models.Film, error) {
 	return &film, nil
 }
 
-// Получить все фильмы с фильтром по жанрам
-func GetAllFilms(genres []uint) ([]models.Film, error) {
+// Получить все фильмы с фильтром по жанрам; с viewerID — только доступные зрителю по возрасту
+func GetAllFilms(genres []uint, viewerID uint) ([]models.Film, error) {
 	var films []models.Film
 	query := db.DB.Preload("Posters").Preload("Genres")
 
+	// только фильмы, которые зрителю можно смотреть по возрасту на сегодня
+	if viewerID != 0 {
+		birth, err := viewerBirthDay(viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if birth.IsZero() {
+			query = query.Where("age_rating = 0")
+		} else {
+			query = query.Where("age_rating <= ?", ageOn(birth, time.Now()))
+		}
+	}
+
 	if len(genres) > 0 {
 		// Выбираем только фильмы, у которых есть ВСЕ жанры из списка
 		subQuery := db.DB.Table("film_genres").
@@ -0,0 +1,484 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// текущая версия схемы зала
+const hallStructureVersion = 2
+
+// Схема зала (версия 2).
+// Проходы и разрывы задаются координатами мест: между соседними местами просто пропускается x или y.
+//
+//	{"version": 2, "rows": [{"row": 1, "label": "A", "seats": [
+//	    {"seat": 1, "label": "A1", "type": "vip", "x": 0, "y": 0},
+//	    {"seat": 2, "label": "A2", "type": "standard", "x": 2, "y": 0, "blocked": true}]}]}
+type HallStructure struct {
+	Version int       `json:"version"`
+	Rows    []HallRow `json:"rows"`
+}
+
+type HallRow struct {
+	Row   uint       `json:"row"`
+	Label string     `json:"label,omitempty"`
+	Seats []HallSeat `json:"seats"`
+}
+
+type HallSeat struct {
+	Seat    uint            `json:"seat"`
+	Label   string          `json:"label,omitempty"`
+	Type    models.SeatType `json:"type"`
+	X       float64         `json:"x"`
+	Y       float64         `json:"y"`
+	Blocked bool            `json:"blocked,omitempty"` // место выведено из продажи
+}
+
+// старая схема: только количество мест в ряду
+type hallStructureV1 struct {
+	Rows []struct {
+		Row   uint `json:"row"`
+		Seats uint `json:"seats"`
+	} `json:"rows"`
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Получить залы кинотеатра
+func GetCinemaHalls(cinemaID uint) ([]models.CinemaHall, error) {
+	if err := findCinema(db.DB, cinemaID); err != nil {
+		return nil, err
+	}
+
+	var halls []models.CinemaHall
+	if err := db.DB.Preload("HallType").
+		Where("cinema_id = ? AND deleted_at IS NULL", cinemaID).
+		Order("name ASC").
+		Find(&halls).Error; err != nil {
+		return nil, err
+	}
+	return halls, nil
+}
+
+// Создать зал в кинотеатре
+func CreateHall(cinemaID uint, input dt.CreateHallDTI) (*dt.CreateHallDTO, error) {
+	if err := findCinema(db.DB, cinemaID); err != nil {
+		return nil, err
+	}
+	if err := findHallType(db.DB, input.HallTypeID); err != nil {
+		return nil, err
+	}
+
+	structure, data, err := prepareHallStructure(input.Structure)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCapacity(input.Capacity, structure); err != nil {
+		return nil, err
+	}
+
+	hall := models.CinemaHall{
+		CinemaID:      cinemaID,
+		HallTypeID:    input.HallTypeID,
+		Name:          input.Name,
+		Capacity:      input.Capacity,
+		Structure:     data,
+		BufferMinutes: input.BufferMinutes,
+	}
+	if err := db.DB.Create(&hall).Error; err != nil {
+		return nil, err
+	}
+
+	return &dt.CreateHallDTO{ID: hall.ID}, nil
+}
+
+// Обновить зал (частично). Если в зале есть предстоящие сеансы,
+// из схемы нельзя убирать места и закрывать места с действующими бронями
+func UpdateHall(cinemaID, hallID uint, input dt.UpdateHallDTI) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		hall, err := lockHall(tx, hallID)
+		if err != nil {
+			return err
+		}
+		if hall.CinemaID != cinemaID {
+			return gorm.ErrRecordNotFound
+		}
+
+		updates := make(map[string]interface{})
+		if input.Name != nil {
+			if *input.Name == "" {
+				return errors.New("название зала не может быть пустым")
+			}
+			updates["name"] = *input.Name
+		}
+		if input.HallTypeID != nil {
+			if err := findHallType(tx, *input.HallTypeID); err != nil {
+				return err
+			}
+			updates["hall_type_id"] = *input.HallTypeID
+		}
+		if input.BufferMinutes != nil {
+			updates["buffer_minutes"] = *input.BufferMinutes
+		}
+
+		// вместимость и схема проверяются вместе, только если меняется одно из них
+		if input.Capacity != nil || len(input.Structure) > 0 {
+			capacity := hall.Capacity
+			if input.Capacity != nil {
+				capacity = *input.Capacity
+			}
+
+			var structure *HallStructure
+			if len(input.Structure) > 0 {
+				var data datatypes.JSON
+				structure, data, err = prepareHallStructure(input.Structure)
+				if err != nil {
+					return err
+				}
+				if err := checkLayoutChange(tx, hall, structure); err != nil {
+					return err
+				}
+				updates["structure"] = data
+			} else {
+				structure, err = parseHallStructure(hall.Structure)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := checkCapacity(capacity, structure); err != nil {
+				return err
+			}
+			updates["capacity"] = capacity
+		}
+
+		if len(updates) == 0 {
+			return errors.New("пустой запрос")
+		}
+
+		return tx.Model(&models.CinemaHall{}).Where("id = ?", hallID).Updates(updates).Error
+	})
+}
+
+// Заменить схему зала. Схема проверяется и сохраняется в актуальной версии, вместимость пересчитывается
+func UpdateHallStructure(hallID uint, raw json.RawMessage) (*HallStructure, error) {
+	structure, data, err := prepareHallStructure(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		hall, err := lockHall(tx, hallID)
+		if err != nil {
+			return err
+		}
+		if err := checkLayoutChange(tx, hall, structure); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.CinemaHall{}).
+			Where("id = ?", hallID).
+			Updates(map[string]interface{}{
+				"structure": data,
+				"capacity":  structure.SeatCount(),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return structure, nil
+}
+
+// Удалить зал. Зал с предстоящими сеансами удалить нельзя;
+// запись помечается удалённой, чтобы прошедшие сеансы и брони остались в истории
+func DeleteHall(cinemaID, hallID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		hall, err := lockHall(tx, hallID)
+		if err != nil {
+			return err
+		}
+		if hall.CinemaID != cinemaID {
+			return gorm.ErrRecordNotFound
+		}
+
+		future, err := hasFutureSessions(tx, hallID)
+		if err != nil {
+			return err
+		}
+		if future {
+			return ErrHallInUse
+		}
+
+		return tx.Model(&models.CinemaHall{}).
+			Where("id = ?", hallID).
+			Update("deleted_at", time.Now()).Error
+	})
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// разбор и проверка схемы из запроса; возвращает схему в актуальной версии и готовый JSON
+func prepareHallStructure(raw json.RawMessage) (*HallStructure, datatypes.JSON, error) {
+	structure, err := parseHallStructure(datatypes.JSON(raw))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidLayout, err)
+	}
+	if err := structure.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidLayout, err)
+	}
+
+	data, err := json.Marshal(structure)
+	if err != nil {
+		return nil, nil, err
+	}
+	return structure, datatypes.JSON(data), nil
+}
+
+// вместимость зала должна совпадать с числом мест в схеме
+func checkCapacity(capacity uint, structure *HallStructure) error {
+	if count := structure.SeatCount(); capacity != count {
+		return fmt.Errorf("%w: вместимость %d не совпадает с числом мест в схеме (%d)", ErrInvalidLayout, capacity, count)
+	}
+	return nil
+}
+
+// проверка, что новая схема не ломает продажи на предстоящие сеансы:
+// все прежние места остаются, места с действующими бронями не закрываются
+func checkLayoutChange(tx *gorm.DB, hall *models.CinemaHall, next *HallStructure) error {
+	future, err := hasFutureSessions(tx, hall.ID)
+	if err != nil || !future {
+		return err
+	}
+
+	if prev, err := parseHallStructure(hall.Structure); err == nil {
+		for _, row := range prev.Rows {
+			for _, seat := range row.Seats {
+				if next.FindSeat(row.Row, seat.Seat) == nil {
+					return fmt.Errorf("%w: нельзя убрать ряд %d, место %d", ErrHallInUse, row.Row, seat.Seat)
+				}
+			}
+		}
+	}
+
+	var booked []struct {
+		Row  uint
+		Seat uint
+	}
+	if err := occupiedSeats(tx.Model(&models.Booking{})).
+		Select("DISTINCT booking.row_num AS row, booking.seat_num AS seat").
+		Joins("JOIN session ON session.id = booking.session_id").
+		Where("session.hall_id = ? AND session.start_time > ?", hall.ID, time.Now()).
+		Find(&booked).Error; err != nil {
+		return err
+	}
+	for _, b := range booked {
+		seat := next.FindSeat(b.Row, b.Seat)
+		if seat == nil || seat.Blocked {
+			return fmt.Errorf("%w: на ряд %d, место %d есть действующие брони", ErrHallInUse, b.Row, b.Seat)
+		}
+	}
+
+	return nil
+}
+
+// есть ли в зале сеансы, которые ещё не начались
+func hasFutureSessions(tx *gorm.DB, hallID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.Session{}).
+		Where("hall_id = ? AND start_time > ? AND deleted_at IS NULL", hallID, time.Now()).
+		Where("status <> ?", models.SessionCanceled).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// зал с блокировкой строки, чтобы правки схемы не шли параллельно
+func lockHall(tx *gorm.DB, hallID uint) (*models.CinemaHall, error) {
+	var hall models.CinemaHall
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at IS NULL").
+		First(&hall, hallID).Error; err != nil {
+		return nil, err
+	}
+	return &hall, nil
+}
+
+func findCinema(tx *gorm.DB, cinemaID uint) error {
+	var count int64
+	if err := tx.Model(&models.Cinema{}).Where("id = ? AND deleted_at IS NULL", cinemaID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("кинотеатр не найден")
+	}
+	return nil
+}
+
+func findHallType(tx *gorm.DB, hallTypeID uint) error {
+	var count int64
+	if err := tx.Model(&models.HallType{}).Where("id = ?", hallTypeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("тип зала не найден")
+	}
+	return nil
+}
+
+// разбор JSON-структуры зала; схема версии 1 переводится в версию 2
+func parseHallStructure(raw datatypes.JSON) (*HallStructure, error) {
+	var head struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, errors.New("ошибка парсинга структуры зала")
+	}
+
+	switch head.Version {
+	case 0, 1:
+		var old hallStructureV1
+		if err := json.Unmarshal(raw, &old); err != nil {
+			return nil, errors.New("ошибка парсинга структуры зала")
+		}
+		return upgradeHallStructure(&old), nil
+	case hallStructureVersion:
+		var structure HallStructure
+		if err := json.Unmarshal(raw, &structure); err != nil {
+			return nil, errors.New("ошибка парсинга структуры зала")
+		}
+		structure.fillDefaults()
+		return &structure, nil
+	default:
+		return nil, fmt.Errorf("неизвестная версия схемы зала: %d", head.Version)
+	}
+}
+
+// перевод старой схемы: все места обычные, стоят сплошной сеткой
+func upgradeHallStructure(old *hallStructureV1) *HallStructure {
+	structure := &HallStructure{Version: hallStructureVersion}
+	for i, r := range old.Rows {
+		row := HallRow{Row: r.Row}
+		for seat := uint(1); seat <= r.Seats; seat++ {
+			row.Seats = append(row.Seats, HallSeat{
+				Seat: seat,
+				Type: models.SeatStandard,
+				X:    float64(seat - 1),
+				Y:    float64(i),
+			})
+		}
+		structure.Rows = append(structure.Rows, row)
+	}
+	structure.fillDefaults()
+	return structure
+}
+
+// подписи и тип по умолчанию
+func (s *HallStructure) fillDefaults() {
+	for i := range s.Rows {
+		row := &s.Rows[i]
+		if row.Label == "" {
+			row.Label = strconv.FormatUint(uint64(row.Row), 10)
+		}
+		for j := range row.Seats {
+			seat := &row.Seats[j]
+			if seat.Type == "" {
+				seat.Type = models.SeatStandard
+			}
+			if seat.Label == "" {
+				seat.Label = strconv.FormatUint(uint64(seat.Seat), 10)
+			}
+		}
+	}
+}
+
+// проверка схемы перед сохранением
+func (s *HallStructure) Validate() error {
+	if len(s.Rows) == 0 {
+		return errors.New("в схеме зала нет ни одного ряда")
+	}
+
+	rows := make(map[uint]bool)
+	positions := make(map[[2]float64]string)
+	for _, row := range s.Rows {
+		if row.Row == 0 {
+			return errors.New("номер ряда должен быть больше нуля")
+		}
+		if rows[row.Row] {
+			return fmt.Errorf("ряд %d указан дважды", row.Row)
+		}
+		rows[row.Row] = true
+
+		if len(row.Seats) == 0 {
+			return fmt.Errorf("в ряду %d нет мест", row.Row)
+		}
+
+		seats := make(map[uint]bool)
+		for _, seat := range row.Seats {
+			if seat.Seat == 0 {
+				return fmt.Errorf("ряд %d: номер места должен быть больше нуля", row.Row)
+			}
+			if seats[seat.Seat] {
+				return fmt.Errorf("ряд %d: место %d указано дважды", row.Row, seat.Seat)
+			}
+			seats[seat.Seat] = true
+
+			if !isValidSeatType(seat.Type) {
+				return fmt.Errorf("ряд %d, место %d: неизвестный тип места %q", row.Row, seat.Seat, seat.Type)
+			}
+			if seat.X < 0 || seat.Y < 0 {
+				return fmt.Errorf("ряд %d, место %d: координаты не могут быть отрицательными", row.Row, seat.Seat)
+			}
+
+			pos := [2]float64{seat.X, seat.Y}
+			if other, ok := positions[pos]; ok {
+				return fmt.Errorf("ряд %d, место %d: координаты совпадают с местом %s", row.Row, seat.Seat, other)
+			}
+			positions[pos] = fmt.Sprintf("%d-%d", row.Row, seat.Seat)
+		}
+	}
+
+	return nil
+}
+
+// место из схемы; nil, если такого нет
+func (s *HallStructure) FindSeat(row, seat uint) *HallSeat {
+	for i := range s.Rows {
+		if s.Rows[i].Row != row {
+			continue
+		}
+		for j := range s.Rows[i].Seats {
+			if s.Rows[i].Seats[j].Seat == seat {
+				return &s.Rows[i].Seats[j]
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// количество мест в схеме, включая закрытые
+func (s *HallStructure) SeatCount() uint {
+	var n uint
+	for _, row := range s.Rows {
+		n += uint(len(row.Seats))
+	}
+	return n
+}
+
+func isValidSeatType(t models.SeatType) bool {
+	switch t {
+	case models.SeatStandard, models.SeatVIP, models.SeatCouch, models.SeatWheelchair:
+		return true
+	}
+	return false
+}
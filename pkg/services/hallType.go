@@ -0,0 +1,90 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Получить все типы залов
+func GetHallTypes() ([]models.HallType, error) {
+	var types []models.HallType
+	if err := db.DB.Order("name ASC").Find(&types).Error; err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// Создать тип зала
+func CreateHallType(input dt.CreateHallTypeDTI) (*dt.CreateHallTypeDTO, error) {
+	var count int64
+	db.DB.Model(&models.HallType{}).Where("name = ?", input.Name).Count(&count)
+	if count > 0 {
+		return nil, errors.New("тип зала с таким названием уже существует")
+	}
+
+	hallType := models.HallType{
+		Name: input.Name,
+		Desc: input.Desc,
+	}
+
+	if err := db.DB.Create(&hallType).Error; err != nil {
+		return nil, err
+	}
+
+	return &dt.CreateHallTypeDTO{ID: hallType.ID}, nil
+}
+
+// Обновить тип зала (частично)
+func UpdateHallType(id uint, updates map[string]interface{}) error {
+	filtered := FilterUpdates(onlyFields(updates, "name", "desc"))
+	if len(filtered) == 0 {
+		return errors.New("пустой запрос")
+	}
+
+	res := db.DB.Model(&models.HallType{}).
+		Where("id = ?", id).
+		Updates(filtered)
+	if res.Error != nil {
+		return errors.New("ошибка при обновлении типа зала")
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("тип зала не найден")
+	}
+	return nil
+}
+
+// Удалить тип зала, если он нигде не используется
+func DeleteHallType(id uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var halls int64
+		if err := tx.Model(&models.CinemaHall{}).Where("hall_type_id = ?", id).Count(&halls).Error; err != nil {
+			return err
+		}
+		if halls > 0 {
+			return fmt.Errorf("%w: тип назначен залам", ErrInUse)
+		}
+
+		var policies int64
+		if err := tx.Model(&models.CancellationPolicy{}).Where("hall_type_id = ?", id).Count(&policies).Error; err != nil {
+			return err
+		}
+		if policies > 0 {
+			return fmt.Errorf("%w: тип указан в политике отмены", ErrInUse)
+		}
+
+		res := tx.Delete(&models.HallType{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
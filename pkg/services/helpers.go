@@ -1,7 +1,9 @@
 package services
 
 import (
+	"CinemaBooking/pkg/money"
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -43,3 +45,47 @@ func FilterUpdates(updates map[string]interface{}) map[string]interface{} {
 
 	return filtered
 }
+
+// нормализует параметры пагинации: страница с 1, размер страницы от 1 до 100
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
+// оставляет в updates только разрешённые поля
+func onlyFields(updates map[string]interface{}, fields ...string) map[string]interface{} {
+	allowed := make(map[string]interface{})
+	for _, f := range fields {
+		if v, ok := updates[f]; ok {
+			allowed[f] = v
+		}
+	}
+	return allowed
+}
+
+// целое неотрицательное число из JSON (encoding/json отдаёт числа как float64)
+func jsonUint(v interface{}) (uint, bool) {
+	f, ok := v.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return 0, false
+	}
+	return uint(f), true
+}
+
+// денежная сумма из JSON (encoding/json отдаёт числа как float64); точнее копейки — ошибка
+func jsonMoney(v interface{}) (money.Money, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return 0, false
+	}
+	m, err := money.FromFloatExact(f)
+	return m, err == nil
+}
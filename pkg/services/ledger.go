@@ -0,0 +1,404 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// движение по счёту для проводки; у общих счетов userID не учитывается
+type posting struct {
+	kind   models.LedgerAccountKind
+	userID uint
+	amount money.Money
+}
+
+type ledgerKey struct {
+	kind   models.LedgerAccountKind
+	userID uint
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Сверить журнал: каждая проводка сбалансирована отдельно по деньгам и по бонусам,
+// а кэшированные балансы профилей равны сумме движений по счетам пользователей
+func CheckLedger() (*dt.LedgerCheckDTO, error) {
+	result := &dt.LedgerCheckDTO{
+		CheckedAt:         time.Now(),
+		UnbalancedEntries: []uint{},
+		Drift:             []dt.LedgerDriftDTO{},
+		SystemBalances:    map[string]money.Money{},
+	}
+
+	if err := db.DB.Raw(`SELECT DISTINCT p.entry_id
+		FROM ledger_posting p
+		JOIN ledger_account a ON a.id = p.account_id
+		GROUP BY p.entry_id, a.kind IN ?
+		HAVING SUM(p.amount) <> 0
+		ORDER BY p.entry_id`, bonusAccountKinds).
+		Scan(&result.UnbalancedEntries).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.DB.Raw(`SELECT u.id AS user_id, k.kind AS account,
+			CASE k.kind WHEN ? THEN pr.balance ELSE pr.bonus END AS cached,
+			COALESCE(SUM(p.amount), 0) AS ledger
+		FROM "user" u
+		JOIN profile pr ON pr.id = u.profile_id
+		CROSS JOIN (VALUES (?), (?)) AS k(kind)
+		LEFT JOIN ledger_account a ON a.user_id = u.id AND a.kind = k.kind
+		LEFT JOIN ledger_posting p ON p.account_id = a.id
+		GROUP BY u.id, k.kind, pr.balance, pr.bonus
+		HAVING CASE k.kind WHEN ? THEN pr.balance ELSE pr.bonus END <> COALESCE(SUM(p.amount), 0)
+		ORDER BY u.id, k.kind`,
+		models.AccountWallet, models.AccountWallet, models.AccountBonus, models.AccountWallet).
+		Scan(&result.Drift).Error; err != nil {
+		return nil, err
+	}
+
+	var system []struct {
+		Kind    string
+		Balance money.Money
+	}
+	if err := db.DB.Model(&models.LedgerAccount{}).
+		Select("ledger_account.kind, COALESCE(SUM(ledger_posting.amount), 0) AS balance").
+		Joins("LEFT JOIN ledger_posting ON ledger_posting.account_id = ledger_account.id").
+		Where("ledger_account.user_id = 0").
+		Group("ledger_account.kind").
+		Scan(&system).Error; err != nil {
+		return nil, err
+	}
+	for _, s := range system {
+		result.SystemBalances[s.Kind] = s.Balance
+	}
+
+	result.Consistent = len(result.UnbalancedEntries) == 0 && len(result.Drift) == 0
+	return result, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// Перенести в журнал всё, что накопилось до него: для каждого пользователя без счетов в журнале
+// переносится старая история платежей и бонусов, а расхождение с текущим балансом закрывается входящим остатком.
+// Повторный запуск пользователей со счетами не трогает
+func InitLedger() error {
+	var userIDs []uint
+	if err := db.DB.Model(&models.User{}).
+		Where(`NOT EXISTS (SELECT 1 FROM ledger_account WHERE ledger_account.kind = ? AND ledger_account.user_id = "user".id)`,
+			models.AccountWallet).
+		Order("id ASC").
+		Pluck("id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if err := db.DB.Transaction(func(tx *gorm.DB) error {
+			return openLedgerAccounts(tx, userID)
+		}); err != nil {
+			return fmt.Errorf("перенос в журнал пользователя %d: %w", userID, err)
+		}
+	}
+	if len(userIDs) > 0 {
+		log.Printf("Перенесено в журнал проводок пользователей: %d", len(userIDs))
+	}
+	return nil
+}
+
+// Фоновый процесс, периодически сверяющий журнал с кэшированными балансами
+func StartLedgerChecker(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			check, err := CheckLedger()
+			if err != nil {
+				log.Printf("Ошибка при сверке журнала проводок: %v", err)
+				continue
+			}
+			if len(check.UnbalancedEntries) > 0 {
+				log.Printf("Несбалансированные проводки: %v", check.UnbalancedEntries)
+			}
+			for _, d := range check.Drift {
+				log.Printf("Расхождение баланса пользователя %d (%s): в профиле %s, в журнале %s",
+					d.UserID, d.Account, d.Cached, d.Ledger)
+			}
+		}
+	}()
+}
+
+// счета, которые ведутся в бонусах; остальные — в деньгах
+var bonusAccountKinds = []models.LedgerAccountKind{models.AccountBonus, models.AccountBonusPool}
+
+func isBonusAccount(kind models.LedgerAccountKind) bool {
+	return kind == models.AccountBonus || kind == models.AccountBonusPool
+}
+
+func isUserAccount(kind models.LedgerAccountKind) bool {
+	return kind == models.AccountWallet || kind == models.AccountBonus || kind == models.AccountDebt
+}
+
+// счета, остаток которых кэшируется в профиле и не может уйти в минус
+func isProfileAccount(kind models.LedgerAccountKind) bool {
+	return kind == models.AccountWallet || kind == models.AccountBonus
+}
+
+// Записать проводку и обновить кэшированные балансы профилей. Движения по деньгам и по бонусам
+// должны сходиться в ноль каждые отдельно; баланс и бонусы пользователя не могут уйти в минус.
+// Нулевые движения пропускаются, проводка без движений не пишется
+func post(tx *gorm.DB, entry models.LedgerEntry, postings ...posting) error {
+	if strings.TrimSpace(entry.Description) == "" {
+		return errors.New("у проводки нет описания")
+	}
+
+	lines := make([]posting, 0, len(postings))
+	var cash, bonus money.Money
+	net := map[ledgerKey]money.Money{}
+	var users []ledgerKey
+	for _, p := range postings {
+		if p.amount == 0 {
+			continue
+		}
+		if !isUserAccount(p.kind) {
+			p.userID = 0
+		}
+		lines = append(lines, p)
+
+		if isBonusAccount(p.kind) {
+			bonus += p.amount
+		} else {
+			cash += p.amount
+		}
+		if isProfileAccount(p.kind) {
+			key := ledgerKey{p.kind, p.userID}
+			if _, ok := net[key]; !ok {
+				users = append(users, key)
+			}
+			net[key] += p.amount
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	if cash != 0 || bonus != 0 {
+		return fmt.Errorf("несбалансированная проводка %q: деньги %s, бонусы %s", entry.Description, cash, bonus)
+	}
+
+	// Кэш в профиле меняется только вместе с журналом
+	for _, key := range users {
+		delta := net[key]
+		if delta == 0 {
+			continue
+		}
+		profile, err := lockProfile(tx, key.userID)
+		if err != nil {
+			return err
+		}
+		column, current, insufficient := "balance", profile.Balance, ErrInsufficientFunds
+		if key.kind == models.AccountBonus {
+			column, current, insufficient = "bonus", profile.Bonus, ErrInsufficientBonus
+		}
+		if current+delta < 0 {
+			return insufficient
+		}
+		if err := tx.Model(profile).
+			Update(column, gorm.Expr(column+" + ?", delta)).Error; err != nil {
+			return err
+		}
+	}
+
+	return insertEntry(tx, &entry, lines)
+}
+
+// записать проводку как есть, без проверок и без обновления кэша
+func insertEntry(tx *gorm.DB, entry *models.LedgerEntry, lines []posting) error {
+	entry.Postings = make([]models.LedgerPosting, 0, len(lines))
+	for _, p := range lines {
+		account, err := ledgerAccount(tx, p.kind, p.userID)
+		if err != nil {
+			return err
+		}
+		entry.Postings = append(entry.Postings, models.LedgerPosting{
+			AccountID: account.ID,
+			Amount:    p.amount,
+		})
+	}
+	return tx.Create(entry).Error
+}
+
+// счёт журнала; создаётся при первом обращении
+func ledgerAccount(tx *gorm.DB, kind models.LedgerAccountKind, userID uint) (*models.LedgerAccount, error) {
+	if !isUserAccount(kind) {
+		userID = 0
+	}
+
+	var account models.LedgerAccount
+	err := tx.Where("kind = ? AND user_id = ?", kind, userID).First(&account).Error
+	if err == nil {
+		return &account, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// счёт мог создать параллельный запрос — тогда вставка ничего не сделает и счёт перечитывается
+	account = models.LedgerAccount{Kind: kind, UserID: userID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+		return nil, err
+	}
+	if account.ID == 0 {
+		if err := tx.Where("kind = ? AND user_id = ?", kind, userID).First(&account).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &account, nil
+}
+
+// сумма движений по счёту
+func ledgerBalance(tx *gorm.DB, kind models.LedgerAccountKind, userID uint) (money.Money, error) {
+	var sum money.Money
+	err := tx.Model(&models.LedgerPosting{}).
+		Joins("JOIN ledger_account ON ledger_account.id = ledger_posting.account_id").
+		Where("ledger_account.kind = ? AND ledger_account.user_id = ?", kind, userID).
+		Select("COALESCE(SUM(ledger_posting.amount), 0)").
+		Scan(&sum).Error
+	return sum, err
+}
+
+// движения по бонусам при оплате: списание потраченных и начисление за покупку
+func bonusPostings(userID uint, spend, received money.Money) []posting {
+	return []posting{
+		{models.AccountBonus, userID, -spend},
+		{models.AccountBonusPool, 0, spend},
+		{models.AccountBonus, userID, received},
+		{models.AccountBonusPool, 0, -received},
+	}
+}
+
+type historyRow struct {
+	ID          uint
+	Amount      money.Money
+	Description string
+	CreatedAt   time.Time
+}
+
+// движения по счёту пользователя с описанием проводки
+func accountHistory(kind models.LedgerAccountKind, userID uint) ([]historyRow, error) {
+	var rows []historyRow
+	err := db.DB.Model(&models.LedgerPosting{}).
+		Select("ledger_posting.id, ledger_posting.amount, ledger_entry.description, ledger_entry.created_at").
+		Joins("JOIN ledger_entry ON ledger_entry.id = ledger_posting.entry_id").
+		Joins("JOIN ledger_account ON ledger_account.id = ledger_posting.account_id").
+		Where("ledger_account.kind = ? AND ledger_account.user_id = ?", kind, userID).
+		Order("ledger_entry.created_at DESC, ledger_posting.id DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// перенос пользователя в журнал: старая история с исправленными знаками, затем входящий остаток
+// до текущего кэшированного баланса. Кэш в профиле при этом не меняется
+func openLedgerAccounts(tx *gorm.DB, userID uint) error {
+	profile, err := lockProfile(tx, userID)
+	if err != nil {
+		return err
+	}
+
+	// в старой истории знак суммы не согласован с операцией, поэтому знак берём из операции
+	var payments []models.PaymentHistory
+	if err := tx.Where("user_id = ? AND deleted_at IS NULL", userID).Order("id ASC").Find(&payments).Error; err != nil {
+		return err
+	}
+	for _, p := range payments {
+		amount := p.Amount.Abs()
+		if p.Operation == models.PaymentSpend {
+			amount = -amount
+		}
+		if amount == 0 {
+			continue
+		}
+		entry := models.LedgerEntry{
+			CreatedAt:   p.CreatedAt,
+			Operation:   models.LedgerLegacy,
+			Description: legacyDescription(p.Desc, "Операция по балансу"),
+			UserID:      userID,
+		}
+		if err := insertEntry(tx, &entry, []posting{
+			{models.AccountWallet, userID, amount},
+			{models.AccountOpening, 0, -amount},
+		}); err != nil {
+			return err
+		}
+	}
+
+	var bonuses []models.BonusHistory
+	if err := tx.Where("user_id = ? AND deleted_at IS NULL", userID).Order("id ASC").Find(&bonuses).Error; err != nil {
+		return err
+	}
+	for _, b := range bonuses {
+		amount := b.Amount.Abs()
+		if b.Operation == models.BonusRedeem {
+			amount = -amount
+		}
+		if amount == 0 {
+			continue
+		}
+		entry := models.LedgerEntry{
+			CreatedAt:   b.CreatedAt,
+			Operation:   models.LedgerLegacy,
+			Description: legacyDescription(b.Desc, "Операция по бонусам"),
+			UserID:      userID,
+		}
+		if err := insertEntry(tx, &entry, []posting{
+			{models.AccountBonus, userID, amount},
+			{models.AccountBonusPool, 0, -amount},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// входящие остатки: всё, что не объясняется старой историей
+	for _, acc := range []struct {
+		kind, counter models.LedgerAccountKind
+		cached        money.Money
+	}{
+		{models.AccountWallet, models.AccountOpening, profile.Balance},
+		{models.AccountBonus, models.AccountBonusPool, profile.Bonus},
+	} {
+		sum, err := ledgerBalance(tx, acc.kind, userID)
+		if err != nil {
+			return err
+		}
+		if diff := acc.cached - sum; diff != 0 {
+			entry := models.LedgerEntry{
+				Operation:   models.LedgerOpening,
+				Description: "Входящий остаток при переходе на журнал проводок",
+				UserID:      userID,
+			}
+			if err := insertEntry(tx, &entry, []posting{
+				{acc.kind, userID, diff},
+				{acc.counter, 0, -diff},
+			}); err != nil {
+				return err
+			}
+		}
+		// счёт создаётся и без движений — по нему InitLedger понимает, что пользователь уже перенесён
+		if _, err := ledgerAccount(tx, acc.kind, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func legacyDescription(desc, fallback string) string {
+	if desc = strings.TrimSpace(desc); desc != "" {
+		return desc
+	}
+	return fallback + " до перехода на журнал проводок"
+}
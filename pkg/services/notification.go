@@ -0,0 +1,112 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// виды уведомлений
+const (
+	notifySessionCanceled = "session_canceled"
+	notifyHoldExpired     = "hold_expired"
+)
+
+// сколько уведомлений отправлять за один проход
+const notifyBatchSize = 100
+
+// Получить свои уведомления, новые сверху
+func GetMyNotifications(userID uint) ([]dt.NotificationDTO, error) {
+	var notifications []models.Notification
+	if err := db.DB.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dt.NotificationDTO, 0, len(notifications))
+	for _, n := range notifications {
+		result = append(result, dt.NotificationDTO{
+			ID:        n.ID,
+			Kind:      n.Kind,
+			Title:     n.Title,
+			Body:      n.Body,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// Отправить накопившиеся уведомления. Внешнего канала доставки пока нет, поэтому уведомление
+// пишется в лог и помечается отправленным; пользователь видит его в своём списке
+func DispatchNotifications() (int, error) {
+	sent := 0
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED — несколько экземпляров сервиса не отправят одно уведомление дважды
+		var pending []models.Notification
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.NotificationPending).
+			Order("id ASC").
+			Limit(notifyBatchSize).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(pending))
+		for i, n := range pending {
+			log.Printf("Уведомление #%d пользователю %d: %s", n.ID, n.UserID, n.Title)
+			ids[i] = n.ID
+		}
+
+		if err := tx.Model(&models.Notification{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":  models.NotificationSent,
+				"sent_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		sent = len(ids)
+		return nil
+	})
+	return sent, err
+}
+
+// Фоновый процесс, периодически отправляющий уведомления из очереди
+func StartNotificationDispatcher(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			sent, err := DispatchNotifications()
+			if err != nil {
+				log.Printf("Ошибка при отправке уведомлений: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("Отправлено уведомлений: %d", sent)
+			}
+		}
+	}()
+}
+
+// поставить уведомление в очередь в той же транзакции, что и событие, о котором оно сообщает
+func enqueueNotification(tx *gorm.DB, userID uint, kind, title, body string) error {
+	return tx.Create(&models.Notification{
+		UserID: userID,
+		Kind:   kind,
+		Title:  title,
+		Body:   body,
+		Status: models.NotificationPending,
+	}).Error
+}
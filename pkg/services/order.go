@@ -0,0 +1,242 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Купить несколько мест на один сеанс одним заказом
+func CreateOrder(input dt.CreateOrderDTI) (*models.Order, error) {
+	seats, err := normalizeSeats(input.Seats)
+	if err != nil {
+		return nil, err
+	}
+
+	var order models.Order
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+
+		// 1. Загружаем сеанс (раньше профиля — в том же порядке, что и при отмене сеанса)
+		session, err := loadBookableSession(tx, input.SessionID)
+		if err != nil {
+			return err
+		}
+
+		// 2. Блокируем профиль пользователя, проверяем возраст и места по схеме зала
+		profile, err := lockProfile(tx, input.UserID)
+		if err != nil {
+			return err
+		}
+		warning, err := checkAgeRating(profile, session)
+		if err != nil {
+			return err
+		}
+		prices := make([]money.Money, len(seats))
+		var orderPrice money.Money
+		for i, s := range seats {
+			seat, err := validateSeat(session, s.RowNum, s.SeatNum)
+			if err != nil {
+				return err
+			}
+			if prices[i], err = seatPrice(session, seat.Type); err != nil {
+				return err
+			}
+			orderPrice += prices[i]
+		}
+
+		// 3. Считаем стоимость всего заказа и создаём его
+		SpendBonus, ReceivedBonus, TotalPrice := calcBookingPrice(orderPrice, profile.Bonus, input.UseBonus)
+		order = models.Order{
+			SessionID:     input.SessionID,
+			CustomerID:    input.UserID,
+			SpendBonus:    SpendBonus,
+			ReceivedBonus: ReceivedBonus,
+			TotalPrice:    TotalPrice,
+			Status:        models.BookingPaid,
+			AgeWarning:    warning,
+		}
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+
+		// 4. Списываем средства за весь заказ один раз
+		if err := chargeForBooking(tx, input.UserID, SpendBonus, ReceivedBonus, TotalPrice, models.LedgerEntry{
+			Description: fmt.Sprintf("Оплата заказа #%d", order.ID),
+			OrderID:     &order.ID,
+		}); err != nil {
+			return err
+		}
+
+		// 5. Занимаем места в порядке (ряд, место), распределяя суммы заказа пропорционально цене мест.
+		// Хотя бы одно место занято — откатывается весь заказ вместе со списанием.
+		spendParts := SpendBonus.Allocate(prices)
+		receivedParts := ReceivedBonus.Allocate(prices)
+		totalParts := TotalPrice.Allocate(prices)
+
+		for i, seat := range seats {
+			booking := models.Booking{
+				OrderID:       &order.ID,
+				SessionID:     input.SessionID,
+				CustomerID:    input.UserID,
+				RowNum:        seat.RowNum,
+				SeatNum:       seat.SeatNum,
+				SpendBonus:    spendParts[i],
+				ReceivedBonus: receivedParts[i],
+				TotalPrice:    totalParts[i],
+				Status:        models.BookingPaid,
+			}
+			if err := reserveSeat(tx, &booking); err != nil {
+				return err
+			}
+			order.Bookings = append(order.Bookings, booking)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Получить свой заказ
+func GetOrder(orderID, userID uint) (*models.Order, error) {
+	var order models.Order
+	if err := db.DB.Preload("Bookings").First(&order, orderID).Error; err != nil {
+		return nil, errors.New("заказ не найден")
+	}
+	if order.CustomerID != userID {
+		return nil, errors.New("нельзя просматривать чужой заказ")
+	}
+
+	return &order, nil
+}
+
+// Отменить заказ целиком
+func CancelOrder(orderID, userID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+
+		var order models.Order
+		if err := tx.First(&order, orderID).Error; err != nil {
+			return errors.New("заказ не найден")
+		}
+		if order.CustomerID != userID {
+			return errors.New("нельзя отменить чужой заказ")
+		}
+		if order.Status != models.BookingPaid {
+			return errors.New("заказ нельзя отменить")
+		}
+
+		// Считаем возврат по политике отмены за места, которые ещё не отменены
+		rest, err := loadOrderRemainder(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		quote, err := quoteRefund(tx, order.SessionID, rest.Total, rest.Spend, rest.Received, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return cancelOrder(tx, &order, rest, userID, "", quote)
+	})
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// проверяет список мест на дубли и сортирует его (ряд, место)
+func normalizeSeats(seats []dt.SeatDTI) ([]dt.SeatDTI, error) {
+	if len(seats) == 0 {
+		return nil, errors.New("не выбрано ни одного места")
+	}
+
+	result := make([]dt.SeatDTI, len(seats))
+	copy(result, seats)
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].RowNum != result[j].RowNum {
+			return result[i].RowNum < result[j].RowNum
+		}
+		return result[i].SeatNum < result[j].SeatNum
+	})
+
+	for i := 1; i < len(result); i++ {
+		if result[i] == result[i-1] {
+			return nil, fmt.Errorf("место указано дважды: ряд %d, место %d", result[i].RowNum, result[i].SeatNum)
+		}
+	}
+
+	return result, nil
+}
+
+// активные места заказа и ещё не возвращённые по ним суммы
+type orderRemainder struct {
+	Bookings []models.Booking
+	Total    money.Money
+	Spend    money.Money
+	Received money.Money
+}
+
+// места заказа, которые ещё оплачены: отдельные места могли быть отменены администратором раньше,
+// и возврат по ним уже сделан, поэтому повторно их не учитываем
+func loadOrderRemainder(tx *gorm.DB, orderID uint) (*orderRemainder, error) {
+	rest := &orderRemainder{}
+	if err := tx.Where("order_id = ? AND status = ?", orderID, models.BookingPaid).
+		Order("id ASC").
+		Find(&rest.Bookings).Error; err != nil {
+		return nil, err
+	}
+
+	for _, b := range rest.Bookings {
+		rest.Total += b.TotalPrice
+		rest.Spend += b.SpendBonus
+		rest.Received += b.ReceivedBonus
+	}
+	return rest, nil
+}
+
+// отмена заказа целиком: один возврат за все оставшиеся места, распределённый по ним пропорционально цене
+func cancelOrder(tx *gorm.DB, order *models.Order, rest *orderRemainder, canceledBy uint, reason string, quote *refundQuote) error {
+	profile, err := lockProfile(tx, order.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	// Возвращаем деньги и потраченные бонусы, снимаем начисленные — за весь заказ
+	if err := refundForBooking(tx, profile, order.CustomerID, quote, models.LedgerEntry{
+		Description: fmt.Sprintf("Возврат за заказ #%d", order.ID),
+		OrderID:     &order.ID,
+	}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	weights := make([]money.Money, len(rest.Bookings))
+	for i := range rest.Bookings {
+		weights[i] = rest.Bookings[i].TotalPrice
+	}
+	refundParts := quote.Money.Allocate(weights)
+	for i := range rest.Bookings {
+		if err := tx.Model(&rest.Bookings[i]).Updates(map[string]interface{}{
+			"status":          models.BookingCanceled,
+			"canceled_at":     now,
+			"canceled_by":     canceledBy,
+			"cancel_reason":   reason,
+			"refunded_amount": refundParts[i],
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(order).Updates(map[string]interface{}{
+		"status":          models.BookingCanceled,
+		"refunded_amount": gorm.Expr("refunded_amount + ?", quote.Money),
+	}).Error
+}
@@ -4,75 +4,40 @@ import (
 	"errors"
 
 	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
 	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// Получить история пополнения баланса
-func GetMyPayments(userID uint) ([]models.PaymentHistory, error) {
-	var payments []models.PaymentHistory
-
-	if err := db.DB.Where("user_id = ?", userID).Find(&payments).Error; err != nil {
+// Получить историю операций по балансу: движения по счёту кошелька в журнале, новые сверху
+func GetMyPayments(userID uint) ([]dt.PaymentHistoryDTO, error) {
+	rows, err := accountHistory(models.AccountWallet, userID)
+	if err != nil {
 		return nil, errors.New("ошибка при получении списка платежей")
 	}
 
-	return payments, nil
-}
-
-// Пополнить баланс
-func RefillMyBalance(userID uint, amount float64) error {
-	if amount <= 0 {
-		return errors.New("сумма пополнения должна быть больше нуля")
-	}
-
-	// Транзакция
-	err := db.DB.Transaction(func(tx *gorm.DB) error {
-		// Находим пользователя
-		var user models.User
-		if err := tx.First(&user, userID).Error; err != nil {
-			return errors.New("пользователь не найден")
+	result := make([]dt.PaymentHistoryDTO, 0, len(rows))
+	for _, r := range rows {
+		op := models.PaymentDeposit
+		if r.Amount < 0 {
+			op = models.PaymentSpend
 		}
-
-		// Находим профиль
-		var profile models.Profile
-		if err := tx.First(&profile, user.ProfileID).Error; err != nil {
-			return errors.New("профиль не найден")
-		}
-
-		// Симулируем оплату через банк
-		if !simulateBankPayment(userID, amount) {
-			return errors.New("платёж не подтвержден банком")
-		}
-
-		// Увеличиваем баланс
-		profile.Balance += amount
-		if err := tx.Save(&profile).Error; err != nil {
-			return errors.New("ошибка при обновлении баланса")
-		}
-
-		// Записываем пополнение в payments
-		payment := models.PaymentHistory{
-			UserID:    userID,
-			Amount:    amount,
-			Operation: models.PaymentDeposit,
-		}
-		if err := tx.Create(&payment).Error; err != nil {
-			return errors.New("ошибка при записи платежа")
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return err
+		result = append(result, dt.PaymentHistoryDTO{
+			ID:        r.ID,
+			Amount:    r.Amount,
+			Desc:      r.Description,
+			Operation: string(op),
+			CreatedAt: r.CreatedAt,
+		})
 	}
-
-	return nil
+	return result, nil
 }
 
 // Получить текущий баланс
-func GetBalance(userID uint) (float64, error) {
+func GetBalance(userID uint) (money.Money, error) {
 	var user models.User
 	if err := db.DB.First(&user, userID).Error; err != nil {
 		return 0, errors.New("пользователь не найден")
@@ -88,48 +53,36 @@ func GetBalance(userID uint) (float64, error) {
 
 // ____________________________________________________INTERNAL____________________________________________________
 // Списать деньги с баланса
-func ChargeFromBalance(userID uint, amount float64) error {
+func ChargeFromBalance(userID uint, amount money.Money, description string) error {
 	if amount <= 0 {
 		return errors.New("некорректная сумма списания")
 	}
 
 	return db.DB.Transaction(func(tx *gorm.DB) error {
-		var user models.User
-		if err := tx.First(&user, userID).Error; err != nil {
-			return errors.New("пользователь не найден")
-		}
-
-		var profile models.Profile
-		if err := tx.First(&profile, user.ProfileID).Error; err != nil {
-			return errors.New("профиль пользователя не найден")
-		}
-
-		if profile.Balance < amount {
-			return errors.New("недостаточно средств на балансе")
-		}
-
-		profile.Balance -= amount
-		if err := tx.Save(&profile).Error; err != nil {
-			return errors.New("ошибка при списании средств с баланса")
-		}
-
-		payment := models.PaymentHistory{
-			UserID:    userID,
-			Amount:    -amount,
-			Operation: models.PaymentSpend,
-		}
-		if err := tx.Create(&payment).Error; err != nil {
-			return errors.New("ошибка при записи платежа")
-		}
-
-		return nil
+		// Деньги с баланса — в выручку
+		return post(tx, models.LedgerEntry{
+			Operation:   models.LedgerCharge,
+			Description: description,
+			UserID:      userID,
+		},
+			posting{models.AccountWallet, userID, -amount},
+			posting{models.AccountRevenue, 0, amount},
+		)
 	})
 }
 
-// симуляция проверки банковского перевода
-func simulateBankPayment(userID uint, value float64) bool {
+// загрузка профиля пользователя с блокировкой строки до конца транзакции
+func lockProfile(tx *gorm.DB, userID uint) (*models.Profile, error) {
+	var user models.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		return nil, errors.New("пользователь не найден")
+	}
+
+	var profile models.Profile
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&profile, user.ProfileID).Error; err != nil {
+		return nil, errors.New("профиль не найден")
+	}
 
-	userID += 1
-	value += 1.0
-	return true
+	return &profile, nil
 }
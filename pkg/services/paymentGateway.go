@@ -0,0 +1,590 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"CinemaBooking/pkg/payments"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// платежи моложе этого возраста сверкой не трогаем — уведомление от шлюза, скорее всего, ещё в пути
+const reconcileDelay = time.Minute
+
+// причина отказа для платежа, которого шлюз не знает
+const lostPaymentReason = "платёж не найден у платёжного шлюза"
+
+// сколько платежей сверять за один проход
+const reconcileBatchSize = 50
+
+// оплаченный платёж нечему зачислить (например, бронь уже снята) — деньги возвращаются на карту
+var errPaymentNotApplicable = errors.New("оплата не может быть зачислена")
+
+// текущий платёжный провайдер; до InitPayments — фейковый в памяти
+var paymentProvider payments.Provider = payments.NewFake()
+
+// секрет подписи уведомлений шлюза; пустой — уведомления не принимаются
+var webhookSecret string
+
+// Подключить платёжный провайдер по имени из конфигурации. Фейковому провайдеру уведомления
+// о смене статуса передаются напрямую, внешний шлюз присылает подписанные события на /payments/webhook
+func InitPayments(name, gatewayURL, secret string) error {
+	p, err := payments.New(name, gatewayURL)
+	if err != nil {
+		return err
+	}
+	if fake, ok := p.(*payments.Fake); ok {
+		fake.OnUpdate = func(intentID string) {
+			if _, err := SyncPayment(intentID); err != nil {
+				log.Printf("Ошибка при обработке платежа %s: %v", intentID, err)
+			}
+		}
+	}
+	paymentProvider = p
+	webhookSecret = secret
+	return nil
+}
+
+// Начать пополнение баланса: платёж создаётся у провайдера и ждёт подтверждения.
+// Баланс не меняется, пока шлюз не подтвердит оплату. Баланс ведётся только в валюте сервиса
+func RefillMyBalance(userID uint, amount money.Money, currency money.Currency) (*dt.RefillDTO, error) {
+	if amount <= 0 {
+		return nil, errors.New("сумма пополнения должна быть больше нуля")
+	}
+	if err := money.RequireDefault(currency); err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		return nil, errors.New("пользователь не найден")
+	}
+
+	intent, err := createPaymentIntent(userID, models.PurposeRefill, nil, amount, "Пополнение баланса")
+	if err != nil {
+		return nil, err
+	}
+	result := toRefillDTO(intent)
+	return &result, nil
+}
+
+// Подтвердить пополнение (оплата клиентом). Шлюз может ответить сразу или позже уведомлением;
+// пока ответа нет, пополнение остаётся в статусе pending
+func ConfirmRefill(refillID, userID uint) (*dt.RefillDTO, error) {
+	intent, err := loadMyRefill(refillID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if intent.Status == models.IntentPending {
+		ext, err := paymentProvider.Confirm(intent.ExternalID)
+		if err != nil {
+			return nil, gatewayError(err)
+		}
+		if intent, err = applyPaymentStatus(ext); err != nil {
+			return nil, err
+		}
+	}
+
+	result := toRefillDTO(intent)
+	return &result, nil
+}
+
+// Получить свои пополнения через шлюз, новые сверху
+func GetMyRefills(userID uint) ([]dt.RefillDTO, error) {
+	var intents []models.PaymentIntent
+	if err := db.DB.Where("user_id = ? AND purpose = ?", userID, models.PurposeRefill).
+		Order("id DESC").
+		Find(&intents).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dt.RefillDTO, 0, len(intents))
+	for i := range intents {
+		result = append(result, toRefillDTO(&intents[i]))
+	}
+	return result, nil
+}
+
+// Получить пополнение; незавершённое сверяется со шлюзом на случай потерянного уведомления
+func GetMyRefill(refillID, userID uint) (*dt.RefillDTO, error) {
+	intent, err := loadMyRefill(refillID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status == models.IntentPending {
+		if synced, err := SyncPayment(intent.ExternalID); err == nil {
+			intent = synced
+		} else {
+			log.Printf("Не удалось сверить платёж %s: %v", intent.ExternalID, err)
+		}
+	}
+
+	result := toRefillDTO(intent)
+	return &result, nil
+}
+
+// Подтвердить оплату брони картой (оплата клиентом). Если шлюз ответит позже,
+// место остаётся удержанным, а платёж — в статусе pending до уведомления
+func ConfirmBookingPayment(bookingID, userID uint) (*dt.BookingPaymentDTO, error) {
+	intent, err := loadMyBookingPayment(bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if intent.Status == models.IntentPending {
+		ext, err := paymentProvider.Confirm(intent.ExternalID)
+		if err != nil {
+			return nil, gatewayError(err)
+		}
+		if intent, err = applyPaymentStatus(ext); err != nil {
+			return nil, err
+		}
+	}
+
+	result := toBookingPaymentDTO(intent)
+	return &result, nil
+}
+
+// Получить оплату брони картой; незавершённая или ждущая возврата сверяется со шлюзом на случай потерянного уведомления
+func GetBookingPayment(bookingID, userID uint) (*dt.BookingPaymentDTO, error) {
+	intent, err := loadMyBookingPayment(bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status == models.IntentPending || intent.Status == models.IntentRefundPending {
+		if synced, err := SyncPayment(intent.ExternalID); err == nil {
+			intent = synced
+		} else {
+			log.Printf("Не удалось сверить платёж %s: %v", intent.ExternalID, err)
+		}
+	}
+
+	result := toBookingPaymentDTO(intent)
+	return &result, nil
+}
+
+// Обработать подписанное уведомление шлюза. Из события берутся только ID события и платежа,
+// статус платежа запрашивается у провайдера — как и при сверке (SyncPayment). Событие
+// записывается в той же транзакции, что и смена статуса платежа с зачислением, поэтому повтор
+// события возвращает duplicate и ничего не меняет. Завершённый платёж больше не меняется
+func HandlePaymentWebhook(body []byte, signature string) (duplicate bool, err error) {
+	if webhookSecret == "" {
+		return false, ErrWebhookDisabled
+	}
+	if err := payments.VerifySignature(webhookSecret, signature, body, time.Now()); err != nil {
+		return false, err
+	}
+
+	var event payments.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return false, errors.New("некорректное уведомление")
+	}
+	if event.ID == "" || event.Intent.ID == "" {
+		return false, errors.New("в уведомлении нет ID события или платежа")
+	}
+	ext, err := paymentProvider.Status(event.Intent.ID)
+	if err != nil {
+		return false, gatewayError(err)
+	}
+
+	var intent *models.PaymentIntent
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		record := models.PaymentEvent{
+			EventID:    event.ID,
+			Type:       event.Type,
+			ExternalID: event.Intent.ID,
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			duplicate = true
+			return nil
+		}
+
+		// неизвестный платёж откатывает и запись события — шлюз повторит уведомление позже
+		var err error
+		intent, err = applyPaymentStatusTx(tx, ext)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if intent != nil {
+		refundPending(intent, ext)
+	}
+	return duplicate, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// Сверить платёж со шлюзом и применить его статус. Статус всегда берётся у провайдера,
+// поэтому уведомление достаточно доверять только в части ID платежа
+func SyncPayment(externalID string) (*models.PaymentIntent, error) {
+	ext, err := paymentProvider.Status(externalID)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+	return applyPaymentStatus(ext)
+}
+
+// Сверить зависшие платежи, по которым не пришло уведомление, и повторить не прошедшие возвраты
+func ReconcilePayments() (int, error) {
+	var ids []string
+	if err := db.DB.Model(&models.PaymentIntent{}).
+		Where("(status = ? AND created_at < ?) OR status = ?",
+			models.IntentPending, time.Now().Add(-reconcileDelay), models.IntentRefundPending).
+		Order("id ASC").
+		Limit(reconcileBatchSize).
+		Pluck("external_id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, id := range ids {
+		ext, err := paymentProvider.Status(id)
+		if errors.Is(err, payments.ErrNotFound) {
+			// платёж старше reconcileDelay, а шлюз о нём не знает: уведомления по нему не будет,
+			// поэтому он закрывается как неуспешный, а не остаётся pending навсегда
+			ext = &payments.Intent{ID: id, Status: payments.StatusFailed, FailureReason: lostPaymentReason}
+		} else if err != nil {
+			log.Printf("Не удалось сверить платёж %s: %v", id, gatewayError(err))
+			continue
+		}
+		intent, err := applyPaymentStatus(ext)
+		if err != nil {
+			log.Printf("Не удалось сверить платёж %s: %v", id, err)
+			continue
+		}
+		if intent.Status != models.IntentPending && intent.Status != models.IntentRefundPending {
+			done++
+		}
+	}
+	return done, nil
+}
+
+// Фоновый процесс, периодически сверяющий зависшие платежи
+func StartPaymentReconciler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			done, err := ReconcilePayments()
+			if err != nil {
+				log.Printf("Ошибка при сверке платежей: %v", err)
+				continue
+			}
+			if done > 0 {
+				log.Printf("Завершено платежей при сверке: %d", done)
+			}
+		}
+	}()
+}
+
+// создать платёж у провайдера и сохранить его в статусе pending. Вызывается вне транзакций:
+// запрос к шлюзу не должен выполняться, пока держатся блокировки строк
+func createPaymentIntent(userID uint, purpose models.PaymentPurpose, bookingID *uint, amount money.Money, desc string) (*models.PaymentIntent, error) {
+	reference, err := newPaymentReference(purpose)
+	if err != nil {
+		return nil, err
+	}
+	ext, err := paymentProvider.CreateIntent(payments.IntentRequest{
+		Amount:      amount,
+		Currency:    money.Default,
+		Description: desc,
+		Reference:   reference,
+	})
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	intent := models.PaymentIntent{
+		UserID:     userID,
+		Purpose:    purpose,
+		Amount:     amount,
+		Currency:   money.Default,
+		BookingID:  bookingID,
+		Provider:   paymentProvider.Name(),
+		ExternalID: ext.ID,
+		Status:     models.IntentPending,
+	}
+	if err := db.DB.Create(&intent).Error; err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// применить статус платежа от шлюза. Платёж блокируется, а завершённый не меняется,
+// поэтому повторные и запоздавшие уведомления ничего не зачисляют второй раз
+func applyPaymentStatus(ext *payments.Intent) (*models.PaymentIntent, error) {
+	var intent *models.PaymentIntent
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		intent, err = applyPaymentStatusTx(tx, ext)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refundPending(intent, ext), nil
+}
+
+// то же внутри уже открытой транзакции
+func applyPaymentStatusTx(tx *gorm.DB, ext *payments.Intent) (*models.PaymentIntent, error) {
+	var intent models.PaymentIntent
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("external_id = ?", ext.ID).
+		First(&intent).Error; err != nil {
+		return nil, errors.New("платёж не найден")
+	}
+	if intent.Status != models.IntentPending {
+		return &intent, nil
+	}
+
+	now := time.Now()
+	switch ext.Status {
+	case payments.StatusSucceeded:
+		if ext.Amount != intent.Amount || money.RequireDefault(ext.Currency) != nil {
+			return nil, fmt.Errorf("%w: сумма у шлюза %s %s не совпадает с суммой платежа %s %s",
+				ErrPaymentGateway, ext.Amount, ext.Currency, intent.Amount, intent.Currency)
+		}
+		err := creditPayment(tx, &intent)
+		switch {
+		case err == nil:
+			intent.Status = models.IntentSucceeded
+		case errors.Is(err, errPaymentNotApplicable):
+			// возврат — запрос к шлюзу, поэтому он выполняется после фиксации транзакции (refundPending)
+			intent.Status = models.IntentRefundPending
+			intent.FailureReason = err.Error()
+		default:
+			return nil, err
+		}
+	case payments.StatusFailed:
+		if err := releasePayment(tx, &intent); err != nil {
+			return nil, err
+		}
+		intent.Status = models.IntentFailed
+		intent.FailureReason = ext.FailureReason
+	case payments.StatusRefunded:
+		// возврат уже прошёл у шлюза, а у нас платёж не успел завершиться — зачислять нечего
+		if err := releasePayment(tx, &intent); err != nil {
+			return nil, err
+		}
+		intent.Status = models.IntentRefunded
+		intent.FailureReason = errPaymentNotApplicable.Error()
+	default:
+		// ещё обрабатывается
+		return &intent, nil
+	}
+	intent.CompletedAt = &now
+
+	if err := tx.Model(&intent).Updates(map[string]interface{}{
+		"status":         intent.Status,
+		"failure_reason": intent.FailureReason,
+		"completed_at":   intent.CompletedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// зачислить подтверждённый платёж по его назначению
+func creditPayment(tx *gorm.DB, intent *models.PaymentIntent) error {
+	switch intent.Purpose {
+	case models.PurposeRefill:
+		return post(tx, models.LedgerEntry{
+			Operation:       models.LedgerRefill,
+			Description:     fmt.Sprintf("Пополнение баланса, платёж #%d", intent.ID),
+			UserID:          intent.UserID,
+			PaymentIntentID: &intent.ID,
+		},
+			posting{models.AccountGateway, 0, -intent.Amount},
+			posting{models.AccountWallet, intent.UserID, intent.Amount},
+		)
+	case models.PurposeBooking:
+		return payBookingByCard(tx, intent)
+	default:
+		return fmt.Errorf("неизвестное назначение платежа %q", intent.Purpose)
+	}
+}
+
+// отклонённый платёж: бронь, ждавшая оплаты картой, снимается и освобождает место
+func releasePayment(tx *gorm.DB, intent *models.PaymentIntent) error {
+	if intent.Purpose != models.PurposeBooking || intent.BookingID == nil {
+		return nil
+	}
+	return tx.Model(&models.Booking{}).
+		Where("id = ? AND status = ?", *intent.BookingID, models.BookingReserved).
+		Updates(map[string]interface{}{
+			"status":        models.BookingCanceled,
+			"expires_at":    nil,
+			"canceled_at":   time.Now(),
+			"cancel_reason": "оплата картой отклонена",
+		}).Error
+}
+
+// оплата брони картой прошла: списываем бонусы по расчёту при бронировании и переводим бронь в оплаченную.
+// Если бронь уже снята (истекло удержание, отмена) или бонусов больше не хватает — errPaymentNotApplicable
+func payBookingByCard(tx *gorm.DB, intent *models.PaymentIntent) error {
+	if intent.BookingID == nil {
+		return fmt.Errorf("%w: у платежа нет брони", errPaymentNotApplicable)
+	}
+	var booking models.Booking
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, *intent.BookingID).Error; err != nil {
+		return fmt.Errorf("%w: бронь не найдена", errPaymentNotApplicable)
+	}
+	// пока бронь в статусе reserved, место за ней, даже если срок удержания уже вышел. Просроченную бронь снимает
+	// фоновый ReleaseExpiredHolds или новая бронь того же места; платёж, пришедший после этого, возвращается
+	if booking.Status != models.BookingReserved {
+		return fmt.Errorf("%w: бронь уже снята", errPaymentNotApplicable)
+	}
+
+	profile, err := lockProfile(tx, booking.CustomerID)
+	if err != nil {
+		return err
+	}
+	if profile.Bonus < booking.SpendBonus {
+		return fmt.Errorf("%w: не хватает бонусов", errPaymentNotApplicable)
+	}
+	// деньги пришли с карты, поэтому в выручку они идут из шлюза, а не с баланса
+	if err := post(tx, models.LedgerEntry{
+		Operation:       models.LedgerBookingPayment,
+		Description:     fmt.Sprintf("Оплата картой брони #%d", booking.ID),
+		UserID:          booking.CustomerID,
+		BookingID:       &booking.ID,
+		PaymentIntentID: &intent.ID,
+	}, append(bonusPostings(booking.CustomerID, booking.SpendBonus, booking.ReceivedBonus),
+		posting{models.AccountGateway, 0, -intent.Amount},
+		posting{models.AccountRevenue, 0, intent.Amount},
+	)...); err != nil {
+		return err
+	}
+
+	return tx.Model(&booking).Updates(map[string]interface{}{
+		"status":     models.BookingPaid,
+		"expires_at": nil,
+	}).Error
+}
+
+// выполнить отложенный возврат на карту уже вне транзакции. Не прошёл — платёж остаётся в refund_pending,
+// и возврат повторит сверка; прошёл — платёж становится refunded. Провайдер не даёт вернуть больше суммы платежа,
+// поэтому параллельная попытка возврата получит ошибку и не вернёт деньги второй раз
+func refundPending(intent *models.PaymentIntent, ext *payments.Intent) *models.PaymentIntent {
+	if intent.Status != models.IntentRefundPending {
+		return intent
+	}
+	if err := refundPayment(ext, intent.Amount); err != nil {
+		log.Printf("Не удалось вернуть платёж %s на карту: %v", intent.ExternalID, err)
+		return intent
+	}
+	if err := db.DB.Model(&models.PaymentIntent{}).
+		Where("id = ? AND status = ?", intent.ID, models.IntentRefundPending).
+		Update("status", models.IntentRefunded).Error; err != nil {
+		log.Printf("Не удалось отметить возврат платежа %s: %v", intent.ExternalID, err)
+		return intent
+	}
+	intent.Status = models.IntentRefunded
+	return intent
+}
+
+// вернуть платёж на карту; уже возвращённое повторно не возвращаем.
+// В журнал такой платёж не попадал, поэтому проводки по возврату нет
+func refundPayment(ext *payments.Intent, amount money.Money) error {
+	if ext.Refunded >= amount {
+		return nil
+	}
+	if _, err := paymentProvider.Refund(ext.ID, amount-ext.Refunded); err != nil {
+		return gatewayError(err)
+	}
+	return nil
+}
+
+func loadMyRefill(refillID, userID uint) (*models.PaymentIntent, error) {
+	var intent models.PaymentIntent
+	if err := db.DB.Where("purpose = ?", models.PurposeRefill).First(&intent, refillID).Error; err != nil {
+		return nil, errors.New("платёж не найден")
+	}
+	if intent.UserID != userID {
+		return nil, errors.New("нельзя просматривать чужой платёж")
+	}
+	return &intent, nil
+}
+
+// последний платёж картой по своей брони
+func loadMyBookingPayment(bookingID, userID uint) (*models.PaymentIntent, error) {
+	var booking models.Booking
+	if err := db.DB.First(&booking, bookingID).Error; err != nil {
+		return nil, errors.New("бронирование не найдено")
+	}
+	if booking.CustomerID != userID {
+		return nil, errors.New("нельзя просматривать чужое бронирование")
+	}
+
+	var intent models.PaymentIntent
+	if err := db.DB.Where("booking_id = ? AND purpose = ?", bookingID, models.PurposeBooking).
+		Order("id DESC").
+		First(&intent).Error; err != nil {
+		return nil, errors.New("платёж не найден")
+	}
+	return &intent, nil
+}
+
+// уникальная ссылка на платёж для провайдера: повтор запроса с ней не создаст второй платёж
+func newPaymentReference(purpose models.PaymentPurpose) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return string(purpose) + "_" + hex.EncodeToString(buf), nil
+}
+
+// ошибки провайдера: некорректный запрос отдаём как есть, остальное — сбой шлюза
+func gatewayError(err error) error {
+	if errors.Is(err, payments.ErrInvalidInput) || errors.Is(err, payments.ErrInvalidState) {
+		return err
+	}
+	if errors.Is(err, payments.ErrNotFound) {
+		return errors.New("платёж не найден")
+	}
+	return fmt.Errorf("%w: %v", ErrPaymentGateway, err)
+}
+
+func toRefillDTO(p *models.PaymentIntent) dt.RefillDTO {
+	return dt.RefillDTO{
+		ID:            p.ID,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Status:        string(p.Status),
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt,
+		CompletedAt:   p.CompletedAt,
+	}
+}
+
+func toBookingPaymentDTO(p *models.PaymentIntent) dt.BookingPaymentDTO {
+	result := dt.BookingPaymentDTO{
+		ID:            p.ID,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Status:        string(p.Status),
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt,
+		CompletedAt:   p.CompletedAt,
+	}
+	if p.BookingID != nil {
+		result.BookingID = *p.BookingID
+	}
+	return result
+}
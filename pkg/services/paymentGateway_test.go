@@ -0,0 +1,165 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"CinemaBooking/pkg/payments"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// фейковый провайдер без уведомлений и секрет подписи на время теста
+func useTestGateway(t *testing.T, secret string) *payments.Fake {
+	t.Helper()
+	fake := payments.NewFake()
+	prevProvider, prevSecret := paymentProvider, webhookSecret
+	paymentProvider, webhookSecret = fake, secret
+	t.Cleanup(func() {
+		paymentProvider, webhookSecret = prevProvider, prevSecret
+	})
+	return fake
+}
+
+func signedEvent(t *testing.T, secret, eventID string, intent *payments.Intent) ([]byte, string) {
+	t.Helper()
+	body, err := json.Marshal(payments.Event{
+		ID:      eventID,
+		Type:    payments.EventType(intent.Status),
+		Created: time.Now().Unix(),
+		Intent:  *intent,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body, payments.Sign(secret, body, time.Now())
+}
+
+// Платёж, которого шлюз не знает (например, заглушка перезапустилась), сверка закрывает как неуспешный
+func TestReconcileLostPayment(t *testing.T) {
+	openTestDB(t)
+	useTestGateway(t, "")
+
+	userID := newTestUser(t, 0)
+	intent := models.PaymentIntent{
+		UserID:     userID,
+		Purpose:    models.PurposeRefill,
+		Amount:     100_00,
+		Provider:   "fake",
+		ExternalID: "pi_lost_" + randomDigits(t, 12),
+		Status:     models.IntentPending,
+	}
+	if err := db.DB.Create(&intent).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DB.Model(&intent).
+		Update("created_at", time.Now().Add(-2*reconcileDelay)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// за проход сверяется не больше reconcileBatchSize платежей: в базе могут быть чужие зависшие
+	for i := 0; i < 100; i++ {
+		done, err := ReconcilePayments()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done == 0 {
+			break
+		}
+	}
+
+	if err := db.DB.First(&intent, intent.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if intent.Status != models.IntentFailed || intent.FailureReason == "" || intent.CompletedAt == nil {
+		t.Errorf("потерянный платёж не закрыт: %+v", intent)
+	}
+
+	var user models.User
+	if err := db.DB.Preload("Profile").First(&user, userID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Profile.Balance != 0 {
+		t.Errorf("по неуспешному платежу зачислено %s", user.Profile.Balance)
+	}
+}
+
+func TestHandlePaymentWebhookDeduplicatesEvents(t *testing.T) {
+	openTestDB(t)
+	const secret = "whsec_test"
+	fake := useTestGateway(t, secret)
+
+	userID := newTestUser(t, 0)
+	amount := money.FromMinor(250_00)
+	refill, err := RefillMyBalance(userID, amount, "")
+	if err != nil {
+		t.Fatalf("пополнение: %v", err)
+	}
+	var intent models.PaymentIntent
+	if err := db.DB.First(&intent, refill.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	ext, err := fake.Confirm(intent.ExternalID)
+	if err != nil {
+		t.Fatalf("подтверждение у провайдера: %v", err)
+	}
+
+	eventID := "evt_test_" + randomDigits(t, 12)
+	body, signature := signedEvent(t, secret, eventID, ext)
+
+	duplicate, err := HandlePaymentWebhook(body, signature)
+	if err != nil || duplicate {
+		t.Fatalf("первое событие: duplicate=%v, err=%v", duplicate, err)
+	}
+	// повтор того же события (шлюз не получил ответ) — ничего не меняет
+	duplicate, err = HandlePaymentWebhook(body, signature)
+	if err != nil || !duplicate {
+		t.Fatalf("повтор события: duplicate=%v, err=%v", duplicate, err)
+	}
+	// новое событие по уже завершённому платежу — не повтор, но и второго зачисления нет
+	body, signature = signedEvent(t, secret, eventID+"_late", ext)
+	if duplicate, err = HandlePaymentWebhook(body, signature); err != nil || duplicate {
+		t.Fatalf("запоздавшее событие: duplicate=%v, err=%v", duplicate, err)
+	}
+
+	var events int64
+	if err := db.DB.Model(&models.PaymentEvent{}).Where("event_id = ?", eventID).Count(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if events != 1 {
+		t.Errorf("записей события %s: %d, ожидалась 1", eventID, events)
+	}
+
+	if err := db.DB.First(&intent, refill.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if intent.Status != models.IntentSucceeded {
+		t.Errorf("статус платежа %s, ожидался %s", intent.Status, models.IntentSucceeded)
+	}
+	balance, err := GetBalance(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != amount {
+		t.Errorf("баланс %s, ожидалось %s — пополнение зачислено не один раз", balance, amount)
+	}
+}
+
+func TestHandlePaymentWebhookRejectsBadSignature(t *testing.T) {
+	openTestDB(t)
+	useTestGateway(t, "whsec_test")
+
+	body, signature := signedEvent(t, "other_secret", "evt_forged", &payments.Intent{ID: "pi_forged", Status: payments.StatusSucceeded})
+	if _, err := HandlePaymentWebhook(body, signature); err == nil {
+		t.Fatal("событие с чужой подписью принято")
+	}
+
+	var events int64
+	if err := db.DB.Model(&models.PaymentEvent{}).Where("event_id = ?", "evt_forged").Count(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if events != 0 {
+		t.Errorf("событие с чужой подписью записано")
+	}
+}
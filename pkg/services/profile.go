@@ -78,8 +78,8 @@ func UpdateProfile(userID uint, updates map[string]interface{}) error {
 		return err
 	}
 
-	// Фильтруем входные данные
-	filtered := FilterUpdates(updates)
+	// Фильтруем входные данные: баланс и бонусы — кэш журнала проводок, напрямую их менять нельзя
+	filtered := FilterUpdates(onlyFields(updates, "first_name", "second_name", "phone", "email", "birth_day"))
 	if len(filtered) == 0 {
 		return errors.New("пустой запрос")
 	}
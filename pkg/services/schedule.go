@@ -0,0 +1,128 @@
+package services
+
+import (
+	"CinemaBooking/config"
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Пересечение сеанса с другими сеансами того же зала
+type ScheduleConflictError struct {
+	SessionIDs []uint
+}
+
+func (e *ScheduleConflictError) Error() string {
+	ids := make([]string, len(e.SessionIDs))
+	for i, id := range e.SessionIDs {
+		ids[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return "зал занят в это время, пересечение с сеансами: " + strings.Join(ids, ", ")
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Проверить сеанс на пересечения в зале без сохранения
+func ValidateSessionSchedule(input dt.ValidateSessionDTI) (*dt.ScheduleCheckDTO, error) {
+	var excludeID uint
+	if input.SessionID != nil {
+		excludeID = *input.SessionID
+	}
+
+	var hall models.CinemaHall
+	if err := db.DB.Preload("Cinema").Where("deleted_at IS NULL").First(&hall, input.HallID).Error; err != nil {
+		return nil, errors.New("зал не найден")
+	}
+	loc := cinemaLocation(&hall.Cinema)
+	start, err := parseLocalTime(input.Start, loc)
+	if err != nil {
+		return nil, err
+	}
+	film, err := loadScheduleFilm(db.DB, input.FilmID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := sessionEnd(start, film, &hall)
+	ids, err := findScheduleConflicts(db.DB, &hall, start, end, excludeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dt.ScheduleCheckDTO{
+		OK:          len(ids) == 0,
+		Start:       start.In(loc),
+		End:         end.In(loc),
+		ConflictIDs: ids,
+	}, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// проверка, что сеанс не пересекается с другими в зале. Зал блокируется до конца транзакции,
+// поэтому параллельные правки расписания одного зала выполняются по очереди
+func checkHallSchedule(tx *gorm.DB, hallID, filmID uint, start time.Time, excludeID uint) error {
+	hall, err := lockHall(tx, hallID)
+	if err != nil {
+		return errors.New("зал не найден")
+	}
+	film, err := loadScheduleFilm(tx, filmID)
+	if err != nil {
+		return err
+	}
+
+	ids, err := findScheduleConflicts(tx, hall, start, sessionEnd(start, film, hall), excludeID)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		return &ScheduleConflictError{SessionIDs: ids}
+	}
+	return nil
+}
+
+// сеансы зала, которые пересекаются с интервалом [start, end) с учётом перерыва после каждого
+func findScheduleConflicts(tx *gorm.DB, hall *models.CinemaHall, start, end time.Time, excludeID uint) ([]uint, error) {
+	buffer := int(hallBuffer(hall) / time.Minute)
+
+	query := tx.Model(&models.Session{}).
+		Joins("JOIN film ON film.id = session.film_id").
+		Where("session.hall_id = ? AND session.deleted_at IS NULL", hall.ID).
+		Where("session.status <> ?", models.SessionCanceled).
+		Where("session.start_time < ?", end).
+		Where("session.start_time + make_interval(mins => (COALESCE(film.duration, 0) + ?)::int) > ?", buffer, start)
+	if excludeID != 0 {
+		query = query.Where("session.id <> ?", excludeID)
+	}
+
+	ids := make([]uint, 0)
+	if err := query.Order("session.start_time ASC").Pluck("session.id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// до какого времени зал занят сеансом: фильм плюс перерыв зала
+func sessionEnd(start time.Time, film *models.Film, hall *models.CinemaHall) time.Time {
+	return start.Add(time.Duration(film.Duration)*time.Minute + hallBuffer(hall))
+}
+
+// перерыв после сеанса: свой у зала или значение по умолчанию
+func hallBuffer(hall *models.CinemaHall) time.Duration {
+	if hall.BufferMinutes != nil {
+		return time.Duration(*hall.BufferMinutes) * time.Minute
+	}
+	return config.GetSessionBuffer()
+}
+
+func loadScheduleFilm(tx *gorm.DB, filmID uint) (*models.Film, error) {
+	var film models.Film
+	if err := tx.First(&film, filmID).Error; err != nil {
+		return nil, errors.New("фильм не найден")
+	}
+	return &film, nil
+}
@@ -4,71 +4,37 @@ import (
 	"CinemaBooking/pkg/db"
 	"CinemaBooking/pkg/dt"
 	"CinemaBooking/pkg/models"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
-)
-
-type HallStructure struct {
-	Rows []struct {
-		Row   uint `json:"row"`
-		Seats uint `json:"seats"`
-	} `json:"rows"`
-}
-
-type SeatDTO struct {
-	Row   uint   `json:"row"`
-	Seat  uint   `json:"seat"`
-	State string `json:"state"` // "free" или "taken"
-}
-
-// Получить все предстоящие сеансы (от сегодня и на 2 месяца вперёд)
-func GetAllSessions() ([]models.Session, error) {
-	var sessions []models.Session
-
-	start := time.Now().Truncate(24 * time.Hour)
-	end := start.AddDate(0, 2, 0) // +2 месяца
 
-	if err := db.DB.Preload("Film").Preload("Hall").
-		Where("start_time >= ? AND start_time < ?", start, end).
-		Order("start_time ASC").
-		Find(&sessions).Error; err != nil {
-		return nil, err
-	}
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
-	return sessions, nil
+// Получить все предстоящие сеансы (от сегодня и на 2 месяца вперёд) с фильтрами по параметрам показа
+func GetAllSessions(filter dt.SessionFilterDTI) ([]dt.SessionDTO, error) {
+	return findUpcomingSessions(db.DB, filter)
 }
 
 // Получить предстоящие сеансы по фильму
-func GetSessionsByFilm(filmID uint) ([]models.Session, error) {
-	var sessions []models.Session
-
-	start := time.Now().Truncate(24 * time.Hour)
-	end := start.AddDate(0, 2, 0) // +2 месяца
-
-	if err := db.DB.Preload("Hall").
-		Where("film_id = ? AND start_time >= ? AND start_time < ?", filmID, start, end).
-		Order("start_time ASC").
-		Find(&sessions).Error; err != nil {
-		return nil, err
-	}
-
-	return sessions, nil
+func GetSessionsByFilm(filmID uint, filter dt.SessionFilterDTI) ([]dt.SessionDTO, error) {
+	return findUpcomingSessions(db.DB.Where("session.film_id = ?", filmID), filter)
 }
 
 // Получить свободные места
-func GetAvailableSeats(sessionID uint) ([]SeatDTO, error) {
+func GetAvailableSeats(sessionID uint) ([]dt.SeatDTO, error) {
 	// 1. Находим сеанс с залом
 	var session models.Session
-	if err := db.DB.Preload("Hall").First(&session, sessionID).Error; err != nil {
+	if err := db.DB.Preload("Hall").Preload("Prices").First(&session, sessionID).Error; err != nil {
 		return nil, errors.New("сеанс не найден")
 	}
 
 	// 2. Парсим JSON структуру зала
-	var structure HallStructure
-	if err := json.Unmarshal(session.Hall.Structure, &structure); err != nil {
-		return nil, errors.New("ошибка парсинга структуры зала")
+	structure, err := parseHallStructure(session.Hall.Structure)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. Получаем занятые места
@@ -76,12 +42,9 @@ func GetAvailableSeats(sessionID uint) ([]SeatDTO, error) {
 		Row  uint
 		Seat uint
 	}
-	if err := db.DB.Model(&models.Booking{}).
+	if err := occupiedSeats(db.DB.Model(&models.Booking{})).
 		Select("row_num as row, seat_num as seat").
-		Where("session_id = ? AND status IN ?", sessionID, []models.BookingStatus{
-			models.BookingReserved,
-			models.BookingPaid,
-		}).
+		Where("session_id = ?", sessionID).
 		Find(&taken).Error; err != nil {
 		return nil, err
 	}
@@ -92,19 +55,32 @@ func GetAvailableSeats(sessionID uint) ([]SeatDTO, error) {
 		takenMap[key] = true
 	}
 
-	// 4. Формируем все места
-	var seats []SeatDTO
+	// 4. Формируем карту мест
+	var seats []dt.SeatDTO
 	for _, row := range structure.Rows {
-		for seat := uint(1); seat <= row.Seats; seat++ {
-			key := fmt.Sprintf("%d-%d", row.Row, seat)
+		for _, seat := range row.Seats {
+			key := fmt.Sprintf("%d-%d", row.Row, seat.Seat)
 			state := "free"
-			if takenMap[key] {
+			switch {
+			case takenMap[key]:
 				state = "taken"
+			case seat.Blocked:
+				state = "blocked"
+			}
+			price, err := seatPrice(&session, seat.Type)
+			if err != nil {
+				return nil, err
 			}
-			seats = append(seats, SeatDTO{
-				Row:   row.Row,
-				Seat:  seat,
-				State: state,
+			seats = append(seats, dt.SeatDTO{
+				Row:      row.Row,
+				RowLabel: row.Label,
+				Seat:     seat.Seat,
+				Label:    seat.Label,
+				Type:     string(seat.Type),
+				X:        seat.X,
+				Y:        seat.Y,
+				Price:    price,
+				State:    state,
 			})
 		}
 	}
@@ -114,49 +90,362 @@ func GetAvailableSeats(sessionID uint) ([]SeatDTO, error) {
 
 // ____________________________________________________ADMIN_ONLY____________________________________________________
 // Создать сеанс
-// CreateSession создает новый сеанс
+// CreateSession создает новый сеанс, если зал свободен в это время
 func CreateSession(input dt.CreateSessionDTI) (*dt.CreateSessionDTO, error) {
-	// Проверка даты и цены
-	if input.Start.Before(time.Now()) {
-		return nil, errors.New("нельзя создать сеанс в прошлом")
-	}
+	// Проверка цены; время проверяется в транзакции, когда известен часовой пояс кинотеатра
 	if input.Price <= 0 {
-		return nil, errors.New("цена должна быть больше нуля")
+		return nil, fmt.Errorf("%w: цена должна быть больше нуля", ErrInvalidSession)
+	}
+
+	if input.Surcharge != nil && *input.Surcharge < 0 {
+		return nil, fmt.Errorf("%w: наценка не может быть отрицательной", ErrInvalidSession)
+	}
+
+	prices, err := buildPriceRules(input.Prices)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := buildSessionAttributes(input.SessionAttributesDTI)
+	if err != nil {
+		return nil, err
 	}
 
 	session := models.Session{
-		FilmID:    input.FilmID,
-		HallID:    input.HallID,
-		StartTime: input.Start,
-		Price:     input.Price,
+		FilmID:            input.FilmID,
+		HallID:            input.HallID,
+		Price:             input.Price,
+		Prices:            prices,
+		SessionAttributes: attrs,
 	}
 
-	if err := db.DB.Create(&session).Error; err != nil {
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// время без смещения — местное время кинотеатра, в базе храним UTC
+		loc, err := hallLocation(tx, input.HallID)
+		if err != nil {
+			return err
+		}
+		start, err := parseLocalTime(input.Start, loc)
+		if err != nil {
+			return err
+		}
+		if start.Before(time.Now()) {
+			return fmt.Errorf("%w: нельзя создать сеанс в прошлом", ErrInvalidSession)
+		}
+		session.StartTime = start
+
+		if err := checkHallSchedule(tx, input.HallID, input.FilmID, start, 0); err != nil {
+			return err
+		}
+
+		// Наценка: явно указанная или наценка формата на момент создания
+		if input.Surcharge != nil {
+			session.Surcharge = *input.Surcharge
+		} else {
+			surcharge, err := formatSurcharge(tx, attrs.Format)
+			if err != nil {
+				return err
+			}
+			session.Surcharge = surcharge
+		}
+
+		return tx.Create(&session).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &dt.CreateSessionDTO{ID: session.ID}, nil
 }
 
-// Обновить сеанс (частично)
+// Обновить сеанс (частично): film_id, hall_id, start_time, price и параметры показа.
+// При смене фильма, зала или времени расписание зала проверяется на пересечения
 func UpdateSession(id uint, updates map[string]interface{}) error {
-	filtered := FilterUpdates(updates)
-	if len(filtered) == 0 {
+	filtered := FilterUpdates(onlyFields(updates, "film_id", "hall_id", "start_time", "price"))
+	// параметры показа берём без фильтра: false и пустая строка здесь — осмысленные значения
+	attrUpdates := onlyFields(updates, "format", "audio_language", "subtitle_language", "late_show", "surcharge")
+	if len(filtered) == 0 && len(attrUpdates) == 0 {
 		return errors.New("пустой запрос")
 	}
 
-	if err := db.DB.Model(&models.Session{}).
-		Where("id = ?", id).
-		Updates(filtered).Error; err != nil {
-		return errors.New("ошибка при обновлении сеанса")
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var session models.Session
+		if err := tx.First(&session, id).Error; err != nil {
+			return gorm.ErrRecordNotFound
+		}
+		if session.Status == models.SessionCanceled {
+			return ErrSessionCanceled
+		}
+		if len(attrUpdates) > 0 {
+			changes, err := sessionAttributeUpdates(tx, &session, attrUpdates)
+			if err != nil {
+				return err
+			}
+			for k, v := range changes {
+				filtered[k] = v
+			}
+		}
+
+		filmID, hallID, start := session.FilmID, session.HallID, session.StartTime
+		if v, ok := filtered["film_id"]; ok {
+			n, ok := jsonUint(v)
+			if !ok {
+				return fmt.Errorf("%w: некорректный film_id", ErrInvalidSession)
+			}
+			filmID = n
+			filtered["film_id"] = n
+		}
+		if v, ok := filtered["hall_id"]; ok {
+			n, ok := jsonUint(v)
+			if !ok {
+				return fmt.Errorf("%w: некорректный hall_id", ErrInvalidSession)
+			}
+			if n != session.HallID {
+				var sold int64
+				if err := occupiedSeats(tx.Model(&models.Booking{})).
+					Where("session_id = ?", id).
+					Count(&sold).Error; err != nil {
+					return err
+				}
+				if sold > 0 {
+					return fmt.Errorf("%w: на сеанс уже есть брони, зал менять нельзя", ErrInvalidSession)
+				}
+			}
+			hallID = n
+			filtered["hall_id"] = n
+		}
+		if v, ok := filtered["start_time"]; ok {
+			raw, _ := v.(string)
+			loc, err := hallLocation(tx, hallID)
+			if err != nil {
+				return err
+			}
+			t, err := parseLocalTime(raw, loc)
+			if err != nil {
+				return err
+			}
+			if t.Before(time.Now()) {
+				return fmt.Errorf("%w: нельзя перенести сеанс в прошлое", ErrInvalidSession)
+			}
+			start = t
+			filtered["start_time"] = t
+		}
+		if v, ok := filtered["price"]; ok {
+			price, ok := jsonMoney(v)
+			if !ok || price <= 0 {
+				return fmt.Errorf("%w: цена должна быть больше нуля и указана с точностью до копейки", ErrInvalidSession)
+			}
+			filtered["price"] = price
+		}
+
+		if filmID != session.FilmID || hallID != session.HallID || !start.Equal(session.StartTime) {
+			if err := checkHallSchedule(tx, hallID, filmID, start, id); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.Session{}).
+			Where("id = ?", id).
+			Updates(filtered).Error; err != nil {
+			return errors.New("ошибка при обновлении сеанса")
+		}
+		return nil
+	})
+}
+
+// Отменить сеанс. Сеанс остаётся в базе со статусом canceled; все оплаченные места
+// возвращаются полностью, удержания снимаются, каждому клиенту уходит уведомление
+func CancelSession(sessionID, adminID uint, reason string) (*dt.SessionCancelSummaryDTO, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "Сеанс отменён кинотеатром"
+	}
+
+	summary := &dt.SessionCancelSummaryDTO{
+		SessionID: sessionID,
+		Reason:    reason,
+		Refunds:   make([]dt.CustomerRefundDTO, 0),
+	}
+	customers := make(map[uint]int) // пользователь -> индекс в summary.Refunds
+	addRefund := func(userID uint, seats int, quote *refundQuote) {
+		i, ok := customers[userID]
+		if !ok {
+			summary.Refunds = append(summary.Refunds, dt.CustomerRefundDTO{UserID: userID})
+			i = len(summary.Refunds) - 1
+			customers[userID] = i
+		}
+		r := &summary.Refunds[i]
+		r.Seats += seats
+		summary.Bookings += seats
+		if quote != nil {
+			r.Money += quote.Money
+			r.BonusReturned += quote.BonusReturn
+			r.BonusClawback += quote.BonusClawback
+			summary.RefundedMoney += quote.Money
+			summary.BonusReturned += quote.BonusReturn
+			summary.BonusClawback += quote.BonusClawback
+		}
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// 1. Блокируем сеанс: пока идёт отмена, новые места на него не продаются
+		var session models.Session
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NULL").
+			First(&session, sessionID).Error; err != nil {
+			return gorm.ErrRecordNotFound
+		}
+		if session.Status == models.SessionCanceled {
+			return ErrSessionCanceled
+		}
+		film, err := loadScheduleFilm(tx, session.FilmID)
+		if err != nil {
+			return err
+		}
+		if !time.Now().Before(session.StartTime.Add(time.Duration(film.Duration) * time.Minute)) {
+			return errors.New("сеанс уже завершён")
+		}
+
+		// 2. Заказы: полный возврат за оставшиеся места одним движением на заказ
+		var orders []models.Order
+		if err := tx.Where("session_id = ? AND status = ?", sessionID, models.BookingPaid).
+			Order("id ASC").
+			Find(&orders).Error; err != nil {
+			return err
+		}
+		for i := range orders {
+			rest, err := loadOrderRemainder(tx, orders[i].ID)
+			if err != nil {
+				return err
+			}
+			if len(rest.Bookings) == 0 {
+				if err := syncOrderStatus(tx, orders[i].ID); err != nil {
+					return err
+				}
+				continue
+			}
+			quote := fullRefundQuote(rest.Total, rest.Spend, rest.Received)
+			if err := cancelOrder(tx, &orders[i], rest, adminID, reason, quote); err != nil {
+				return err
+			}
+			summary.Orders++
+			addRefund(orders[i].CustomerID, len(rest.Bookings), quote)
+		}
+
+		// 3. Отдельные места: оплаченные — с полным возвратом, удержанные — просто снимаются
+		var bookings []models.Booking
+		if err := tx.Where("session_id = ? AND order_id IS NULL AND status IN ?", sessionID, []models.BookingStatus{
+			models.BookingReserved,
+			models.BookingPaid,
+		}).
+			Order("id ASC").
+			Find(&bookings).Error; err != nil {
+			return err
+		}
+		for i := range bookings {
+			var quote *refundQuote
+			if bookings[i].Status == models.BookingPaid {
+				quote = fullRefundQuote(bookings[i].TotalPrice, bookings[i].SpendBonus, bookings[i].ReceivedBonus)
+			}
+			if err := cancelBooking(tx, &bookings[i], adminID, reason, quote); err != nil {
+				return err
+			}
+			addRefund(bookings[i].CustomerID, 1, quote)
+		}
+
+		// 4. Уведомляем каждого клиента один раз, с общей суммой возврата
+		loc, err := hallLocation(tx, session.HallID)
+		if err != nil {
+			return err
+		}
+		title := fmt.Sprintf("Сеанс «%s» %s отменён", film.Title, session.StartTime.In(loc).Format("02.01.2006 15:04"))
+		for _, r := range summary.Refunds {
+			body := fmt.Sprintf("Причина: %s. Отменено мест: %d.", reason, r.Seats)
+			if r.Money > 0 || r.BonusReturned > 0 {
+				body += fmt.Sprintf(" На баланс возвращено %s, бонусов возвращено %s.", r.Money, r.BonusReturned)
+			}
+			if err := enqueueNotification(tx, r.UserID, notifySessionCanceled, title, body); err != nil {
+				return err
+			}
+		}
+		summary.Customers = len(summary.Refunds)
+
+		// 5. Помечаем сеанс отменённым
+		return tx.Model(&session).Updates(map[string]interface{}{
+			"status":        models.SessionCanceled,
+			"canceled_at":   time.Now(),
+			"cancel_reason": reason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// не отменённые сеансы от начала сегодняшнего дня и на 2 месяца вперёд;
+// «сегодня» у каждого кинотеатра своё — по его часовому поясу
+func findUpcomingSessions(query *gorm.DB, filter dt.SessionFilterDTI) ([]dt.SessionDTO, error) {
+	query, err := applySessionFilter(query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	localToday := "date_trunc('day', now() AT TIME ZONE cinema.timezone)"
+	var sessions []models.Session
+	if err := query.Preload("Hall.Cinema").
+		Joins("JOIN film ON film.id = session.film_id").
+		Joins("JOIN cinema_hall ON cinema_hall.id = session.hall_id").
+		Joins("JOIN cinema ON cinema.id = cinema_hall.cinema_id").
+		Where("session.start_time >= "+localToday+" AT TIME ZONE cinema.timezone").
+		Where("session.start_time < ("+localToday+" + interval '2 months') AT TIME ZONE cinema.timezone").
+		Where("session.deleted_at IS NULL AND session.status <> ?", models.SessionCanceled).
+		Order("session.start_time ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dt.SessionDTO, 0, len(sessions))
+	for i := range sessions {
+		result = append(result, toSessionDTO(&sessions[i]))
 	}
-	return nil
+	return result, nil
 }
 
-// Удалить сеанс
-func DeleteSession(id uint) error {
-	if err := db.DB.Delete(&models.Session{}, id).Error; err != nil {
-		return err
+// сеанс с залом, на который ещё можно купить билет: не удалён, не отменён и не начался.
+// Сеанс блокируется на чтение, чтобы параллельная отмена сеанса или правка серии дождались конца покупки
+func loadBookableSession(tx *gorm.DB, sessionID uint) (*models.Session, error) {
+	var session models.Session
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Preload("Hall.Cinema").Preload("Film").Preload("Prices").
+		First(&session, sessionID).Error; err != nil {
+		return nil, errors.New("сеанс не найден")
+	}
+	if session.DeletedAt != nil {
+		return nil, errors.New("сеанс не найден")
+	}
+	if session.Status == models.SessionCanceled {
+		return nil, ErrSessionCanceled
+	}
+	if !time.Now().Before(session.StartTime) {
+		return nil, ErrSessionStarted
+	}
+	return &session, nil
+}
+
+// проверка, что место есть в схеме зала сеанса и не закрыто для продажи; возвращает место из схемы
+func validateSeat(session *models.Session, row, seat uint) (*HallSeat, error) {
+	structure, err := parseHallStructure(session.Hall.Structure)
+	if err != nil {
+		return nil, err
+	}
+	found := structure.FindSeat(row, seat)
+	if found == nil {
+		return nil, fmt.Errorf("%w: ряд %d, место %d", ErrInvalidSeat, row, seat)
+	}
+	if found.Blocked {
+		return nil, fmt.Errorf("%w: ряд %d, место %d закрыто для продажи", ErrInvalidSeat, row, seat)
 	}
-	return nil
+	return found, nil
 }
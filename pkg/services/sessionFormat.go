@@ -0,0 +1,252 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// форматы показа в порядке вывода
+var sessionFormats = []models.SessionFormat{
+	models.Format2D,
+	models.Format3D,
+	models.FormatIMAX,
+	models.FormatIMAX3D,
+}
+
+// язык по умолчанию для звуковой дорожки
+const defaultAudioLanguage = "ru"
+
+// возрастное ограничение позднего сеанса
+const lateShowAge = 18
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Наценки по всем форматам; для формата без наценки — 0
+func GetFormatSurcharges() ([]dt.FormatSurchargeDTO, error) {
+	var rows []models.FormatSurcharge
+	if err := db.DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	amounts := make(map[models.SessionFormat]money.Money, len(rows))
+	for _, r := range rows {
+		amounts[r.Format] = r.Amount
+	}
+
+	result := make([]dt.FormatSurchargeDTO, 0, len(sessionFormats))
+	for _, f := range sessionFormats {
+		result = append(result, dt.FormatSurchargeDTO{
+			Format: string(f),
+			Amount: amounts[f],
+		})
+	}
+	return result, nil
+}
+
+// Задать наценку формата. Действует для новых сеансов; у созданных сеансов наценка уже зафиксирована
+func SetFormatSurcharge(format string, amount money.Money) error {
+	f := models.SessionFormat(strings.ToLower(strings.TrimSpace(format)))
+	if !isValidFormat(f) {
+		return fmt.Errorf("%w: неизвестный формат %q", ErrInvalidSession, format)
+	}
+	if amount < 0 {
+		return fmt.Errorf("%w: наценка не может быть отрицательной", ErrInvalidSession)
+	}
+
+	row := models.FormatSurcharge{Format: f, Amount: amount}
+	return db.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "format"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount", "updated_at"}),
+	}).Create(&row).Error
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// проверка и нормализация параметров показа: формат и языки в нижнем регистре, значения по умолчанию
+func buildSessionAttributes(input dt.SessionAttributesDTI) (models.SessionAttributes, error) {
+	attrs := models.SessionAttributes{
+		Format:           models.SessionFormat(strings.ToLower(strings.TrimSpace(input.Format))),
+		AudioLanguage:    strings.ToLower(strings.TrimSpace(input.AudioLanguage)),
+		SubtitleLanguage: strings.ToLower(strings.TrimSpace(input.SubtitleLanguage)),
+		LateShow:         input.LateShow,
+	}
+	if attrs.Format == "" {
+		attrs.Format = models.Format2D
+	}
+	if attrs.AudioLanguage == "" {
+		attrs.AudioLanguage = defaultAudioLanguage
+	}
+
+	if !isValidFormat(attrs.Format) {
+		return attrs, fmt.Errorf("%w: неизвестный формат %q", ErrInvalidSession, input.Format)
+	}
+	if !isValidLanguage(attrs.AudioLanguage) {
+		return attrs, fmt.Errorf("%w: некорректный язык звука %q", ErrInvalidSession, input.AudioLanguage)
+	}
+	if attrs.SubtitleLanguage != "" && !isValidLanguage(attrs.SubtitleLanguage) {
+		return attrs, fmt.Errorf("%w: некорректный язык субтитров %q", ErrInvalidSession, input.SubtitleLanguage)
+	}
+	return attrs, nil
+}
+
+// новые параметры показа из частичного обновления сеанса. При смене формата без явной наценки
+// подставляется наценка нового формата
+func sessionAttributeUpdates(tx *gorm.DB, session *models.Session, raw map[string]interface{}) (map[string]interface{}, error) {
+	input := dt.SessionAttributesDTI{
+		Format:           string(session.Format),
+		AudioLanguage:    session.AudioLanguage,
+		SubtitleLanguage: session.SubtitleLanguage,
+		LateShow:         session.LateShow,
+	}
+	for key, v := range raw {
+		switch key {
+		case "format", "audio_language", "subtitle_language":
+			str, ok := v.(string)
+			if !ok && v != nil {
+				return nil, fmt.Errorf("%w: %s ожидается строкой", ErrInvalidSession, key)
+			}
+			switch key {
+			case "format":
+				input.Format = str
+			case "audio_language":
+				input.AudioLanguage = str
+			default:
+				input.SubtitleLanguage = str
+			}
+		case "late_show":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%w: late_show ожидается true или false", ErrInvalidSession)
+			}
+			input.LateShow = b
+		}
+	}
+
+	attrs, err := buildSessionAttributes(input)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{
+		"format":            attrs.Format,
+		"audio_language":    attrs.AudioLanguage,
+		"subtitle_language": attrs.SubtitleLanguage,
+		"late_show":         attrs.LateShow,
+	}
+
+	if v, ok := raw["surcharge"]; ok {
+		amount, ok := jsonMoney(v)
+		if !ok || amount < 0 {
+			return nil, fmt.Errorf("%w: наценка должна быть неотрицательным числом с точностью до копейки", ErrInvalidSession)
+		}
+		result["surcharge"] = amount
+	} else if attrs.Format != session.Format {
+		amount, err := formatSurcharge(tx, attrs.Format)
+		if err != nil {
+			return nil, err
+		}
+		result["surcharge"] = amount
+	}
+
+	return result, nil
+}
+
+// наценка формата из настроек; если не задана — без наценки
+func formatSurcharge(tx *gorm.DB, format models.SessionFormat) (money.Money, error) {
+	var row models.FormatSurcharge
+	err := tx.Where("format = ?", format).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return row.Amount, nil
+}
+
+// фильтры списка сеансов по параметрам показа и возрасту зрителя
+// (для allowed_only в запросе нужны JOIN film, cinema_hall и cinema)
+func applySessionFilter(query *gorm.DB, filter dt.SessionFilterDTI) (*gorm.DB, error) {
+	if filter.Format != "" {
+		f := models.SessionFormat(strings.ToLower(filter.Format))
+		if !isValidFormat(f) {
+			return nil, fmt.Errorf("%w: неизвестный формат %q", ErrInvalidSession, filter.Format)
+		}
+		query = query.Where("session.format = ?", f)
+	}
+	if filter.Audio != "" {
+		query = query.Where("session.audio_language = ?", strings.ToLower(filter.Audio))
+	}
+	switch strings.ToLower(filter.Subtitles) {
+	case "":
+	case "none":
+		query = query.Where("COALESCE(session.subtitle_language, '') = ''")
+	default:
+		query = query.Where("session.subtitle_language = ?", strings.ToLower(filter.Subtitles))
+	}
+	if filter.LateShow != nil {
+		query = query.Where("session.late_show = ?", *filter.LateShow)
+	}
+	if filter.AllowedOnly {
+		if filter.ViewerID == 0 {
+			return nil, fmt.Errorf("%w: allowed_only доступен только авторизованному пользователю", ErrInvalidSession)
+		}
+		birth, err := viewerBirthDay(filter.ViewerID)
+		if err != nil {
+			return nil, err
+		}
+		query = applyAgeFilter(query, birth)
+	}
+	return query, nil
+}
+
+func isValidFormat(f models.SessionFormat) bool {
+	for _, known := range sessionFormats {
+		if f == known {
+			return true
+		}
+	}
+	return false
+}
+
+// код языка ISO 639: две-три латинские буквы
+func isValidLanguage(code string) bool {
+	if len(code) < 2 || len(code) > 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func toSessionAttributesDTO(a models.SessionAttributes) dt.SessionAttributesDTO {
+	return dt.SessionAttributesDTO{
+		Format:           string(a.Format),
+		AudioLanguage:    a.AudioLanguage,
+		SubtitleLanguage: a.SubtitleLanguage,
+		LateShow:         a.LateShow,
+	}
+}
+
+// сеанс для ответа (зал и кинотеатр сеанса должны быть загружены)
+func toSessionDTO(s *models.Session) dt.SessionDTO {
+	loc := sessionLocation(s)
+	return dt.SessionDTO{
+		ID:                   s.ID,
+		FilmID:               s.FilmID,
+		HallID:               s.HallID,
+		StartTime:            s.StartTime.UTC(),
+		LocalStartTime:       s.StartTime.In(loc),
+		Timezone:             loc.String(),
+		Price:                s.Price,
+		Surcharge:            s.Surcharge,
+		SessionAttributesDTO: toSessionAttributesDTO(s.SessionAttributes),
+	}
+}
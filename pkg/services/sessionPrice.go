@@ -0,0 +1,178 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// наибольший множитель цены категории к базовой цене сеанса
+const maxPriceMultiplier = 100
+
+// категории мест в порядке вывода
+var seatTypes = []models.SeatType{
+	models.SeatStandard,
+	models.SeatVIP,
+	models.SeatCouch,
+	models.SeatWheelchair,
+}
+
+// Цены сеанса по категориям мест; для категорий без правила действует базовая цена
+func GetSessionPrices(sessionID uint) ([]dt.SessionPriceDTO, error) {
+	var session models.Session
+	if err := db.DB.Preload("Prices").First(&session, sessionID).Error; err != nil {
+		return nil, errors.New("сеанс не найден")
+	}
+
+	result := make([]dt.SessionPriceDTO, 0, len(seatTypes))
+	for _, t := range seatTypes {
+		price, err := seatPrice(&session, t)
+		if err != nil {
+			return nil, err
+		}
+		item := dt.SessionPriceDTO{
+			SeatType: string(t),
+			Price:    price,
+		}
+		if rule := findPriceRule(session.Prices, t); rule != nil && rule.MultiplierBP != nil {
+			multiplier := float64(*rule.MultiplierBP) / money.BasisPointsPerUnit
+			item.Multiplier = &multiplier
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Заменить цены категорий мест у сеанса. Уже купленные билеты сохраняют свою цену
+func SetSessionPrices(sessionID uint, input []dt.SessionPriceDTI) error {
+	rules, err := buildPriceRules(input)
+	if err != nil {
+		return err
+	}
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var session models.Session
+		if err := tx.First(&session, sessionID).Error; err != nil {
+			return gorm.ErrRecordNotFound
+		}
+		if session.Status == models.SessionCanceled {
+			return ErrSessionCanceled
+		}
+		if !time.Now().Before(session.StartTime) {
+			return ErrSessionStarted
+		}
+
+		if err := tx.Where("session_id = ?", sessionID).Delete(&models.SessionPrice{}).Error; err != nil {
+			return err
+		}
+		for i := range rules {
+			rules[i].SessionID = sessionID
+		}
+		if len(rules) > 0 {
+			if err := tx.Create(&rules).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// проверка правил цен: известная категория, ровно одно из price/multiplier, без повторов
+func buildPriceRules(input []dt.SessionPriceDTI) ([]models.SessionPrice, error) {
+	seen := make(map[models.SeatType]bool)
+	rules := make([]models.SessionPrice, 0, len(input))
+
+	for _, p := range input {
+		t := models.SeatType(p.SeatType)
+		if !isValidSeatType(t) {
+			return nil, fmt.Errorf("%w: неизвестная категория мест %q", ErrInvalidPrice, p.SeatType)
+		}
+		if seen[t] {
+			return nil, fmt.Errorf("%w: категория %q указана дважды", ErrInvalidPrice, p.SeatType)
+		}
+		seen[t] = true
+
+		if (p.Price == nil) == (p.Multiplier == nil) {
+			return nil, fmt.Errorf("%w: для категории %q нужно указать либо price, либо multiplier", ErrInvalidPrice, p.SeatType)
+		}
+		if p.Price != nil && *p.Price <= 0 {
+			return nil, fmt.Errorf("%w: цена категории %q должна быть больше нуля", ErrInvalidPrice, p.SeatType)
+		}
+		var multiplier *int64
+		if p.Multiplier != nil {
+			bp, err := multiplierBasisPoints(*p.Multiplier)
+			if err != nil {
+				return nil, fmt.Errorf("%w: множитель категории %q %s", ErrInvalidPrice, p.SeatType, err)
+			}
+			multiplier = &bp
+		}
+
+		rules = append(rules, models.SessionPrice{
+			SeatType:     t,
+			Price:        p.Price,
+			MultiplierBP: multiplier,
+		})
+	}
+
+	return rules, nil
+}
+
+// цена места категории на сеансе (правила цен сеанса должны быть загружены).
+// Наценка за формат добавляется к цене любой категории
+func seatPrice(session *models.Session, seatType models.SeatType) (money.Money, error) {
+	price, err := categoryPrice(session, seatType)
+	if err != nil {
+		return 0, err
+	}
+	return price + session.Surcharge, nil
+}
+
+// цена категории без наценки за формат
+func categoryPrice(session *models.Session, seatType models.SeatType) (money.Money, error) {
+	rule := findPriceRule(session.Prices, seatType)
+	switch {
+	case rule == nil:
+		return session.Price, nil
+	case rule.Price != nil:
+		return *rule.Price, nil
+	case rule.MultiplierBP != nil:
+		price, err := session.Price.MulBasisPoints(*rule.MultiplierBP)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidPrice, err)
+		}
+		return price, nil
+	default:
+		return session.Price, nil
+	}
+}
+
+// множитель из запроса в базисных пунктах: больше нуля, не больше maxPriceMultiplier
+// и не точнее 0.0001 — иначе цена зависела бы от округления float64
+func multiplierBasisPoints(m float64) (int64, error) {
+	if math.IsNaN(m) || m <= 0 || m > maxPriceMultiplier {
+		return 0, fmt.Errorf("должен быть больше нуля и не больше %d", maxPriceMultiplier)
+	}
+	bp := math.Round(m * money.BasisPointsPerUnit)
+	if math.Abs(m*money.BasisPointsPerUnit-bp) > 1e-6 {
+		return 0, errors.New("задаётся с точностью до 0.0001")
+	}
+	return int64(bp), nil
+}
+
+func findPriceRule(rules []models.SessionPrice, seatType models.SeatType) *models.SessionPrice {
+	for i := range rules {
+		if rules[i].SeatType == seatType {
+			return &rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package services
+
+import "testing"
+
+func TestMultiplierBasisPoints(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want int64
+		ok   bool
+	}{
+		{1, 10000, true},
+		{1.5, 15000, true},
+		{0.8, 8000, true},
+		{1.2345, 12345, true},
+		{100, 1000000, true},
+		{0, 0, false},
+		{-1.5, 0, false},
+		{100.0001, 0, false},
+		{1.23456, 0, false},
+	}
+	for _, tt := range tests {
+		got, err := multiplierBasisPoints(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("multiplierBasisPoints(%v) = %d, %v; ожидалось %d, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// цена обычного места с наценкой за формат: правило категории standard или базовая цена
+const sessionPriceFromExpr = "(COALESCE(sp.price, ROUND(session.price * sp.multiplier, 2), session.price) + session.surcharge)"
+
+// сортировки поиска сеансов
+var sessionSearchSorts = map[string]string{
+	"start_time": "session.start_time",
+	"price":      sessionPriceFromExpr,
+	"title":      "film.title",
+}
+
+// Поиск предстоящих сеансов по датам, времени суток, кинотеатру, типу зала, жанрам, возрасту,
+// цене, параметрам показа и наличию нескольких свободных мест подряд
+func SearchSessions(filter dt.SessionSearchDTI) (*dt.SessionSearchResultDTO, error) {
+	page, limit := normalizePage(filter.Page, filter.Limit)
+
+	query, err := buildSessionSearch(filter)
+	if err != nil {
+		return nil, err
+	}
+	order, err := sessionSearchOrder(filter.Sort, filter.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	var total int64
+	if filter.AdjacentSeats > 1 {
+		// Свободные места считаются по схеме зала, поэтому этот фильтр применяется после выборки,
+		// а страница вырезается из уже отфильтрованного списка
+		var candidates []sessionCandidate
+		if err := query.Select("session.id, session.hall_id").Order(order).Scan(&candidates).Error; err != nil {
+			return nil, err
+		}
+		matched, err := filterByAdjacentSeats(db.DB, candidates, filter.AdjacentSeats)
+		if err != nil {
+			return nil, err
+		}
+		total = int64(len(matched))
+		from := (page - 1) * limit
+		if from < len(matched) {
+			ids = matched[from:min(from+limit, len(matched))]
+		}
+	} else {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, err
+		}
+		if err := query.Order(order).
+			Offset((page-1)*limit).
+			Limit(limit).
+			Pluck("session.id", &ids).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := loadSessionSearchItems(db.DB, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dt.SessionSearchResultDTO{
+		Items: items,
+		Page:  page,
+		Limit: limit,
+		Total: total,
+	}, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+type sessionCandidate struct {
+	ID     uint
+	HallID uint
+}
+
+// запрос поиска со всеми фильтрами, без сортировки и страницы.
+// Даты сравниваются с местной датой сеанса в поясе его кинотеатра
+func buildSessionSearch(filter dt.SessionSearchDTI) (*gorm.DB, error) {
+	now := time.Now()
+	localToday := "(now() AT TIME ZONE cinema.timezone)::date"
+	fromExpr, toExpr := localToday, localToday+" + interval '2 months'"
+	var fromArgs, toArgs []interface{}
+	// грубые границы в UTC: местные сутки отличаются от UTC не больше чем на сутки, зато работает индекс
+	lower, upper := now, time.Time{}
+
+	if filter.DateFrom != "" {
+		parsed, err := time.Parse("2006-01-02", filter.DateFrom)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date_from ожидается в формате YYYY-MM-DD", ErrInvalidSession)
+		}
+		fromExpr, fromArgs = "?::date", []interface{}{parsed.Format("2006-01-02")}
+		toExpr, toArgs = "?::date + interval '2 months'", fromArgs
+		if l := parsed.AddDate(0, 0, -1); l.After(lower) {
+			lower = l
+		}
+		upper = parsed.AddDate(0, 2, 1)
+	}
+	if filter.DateTo != "" {
+		parsed, err := time.Parse("2006-01-02", filter.DateTo)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date_to ожидается в формате YYYY-MM-DD", ErrInvalidSession)
+		}
+		if filter.DateFrom != "" && filter.DateTo < filter.DateFrom {
+			return nil, fmt.Errorf("%w: date_to раньше date_from", ErrInvalidSession)
+		}
+		toExpr, toArgs = "?::date + 1", []interface{}{parsed.Format("2006-01-02")} // включительно
+		upper = parsed.AddDate(0, 0, 2)
+	}
+
+	local := sessionLocalTimeExpr
+	query := db.DB.Model(&models.Session{}).
+		Joins("JOIN film ON film.id = session.film_id").
+		Joins("JOIN cinema_hall ON cinema_hall.id = session.hall_id").
+		Joins("JOIN cinema ON cinema.id = cinema_hall.cinema_id").
+		Joins("LEFT JOIN session_price sp ON sp.session_id = session.id AND sp.seat_type = ?", models.SeatStandard).
+		Where("session.deleted_at IS NULL AND session.status <> ?", models.SessionCanceled).
+		// уже начавшиеся сеансы не показываем
+		Where("session.start_time >= ?", lower).
+		Where(local+" >= "+fromExpr, fromArgs...).
+		Where(local+" < "+toExpr, toArgs...)
+	if !upper.IsZero() {
+		query = query.Where("session.start_time < ?", upper)
+	}
+
+	if filter.TimeFrom != "" || filter.TimeTo != "" {
+		var err error
+		if query, err = applyTimeOfDay(query, filter.TimeFrom, filter.TimeTo); err != nil {
+			return nil, err
+		}
+	}
+	if filter.CinemaID != 0 {
+		query = query.Where("cinema_hall.cinema_id = ?", filter.CinemaID)
+	}
+	if filter.HallTypeID != 0 {
+		query = query.Where("cinema_hall.hall_type_id = ?", filter.HallTypeID)
+	}
+	if filter.FilmID != 0 {
+		query = query.Where("session.film_id = ?", filter.FilmID)
+	}
+	if filter.Genres != "" {
+		genres, err := parseIDList(filter.Genres)
+		if err != nil {
+			return nil, fmt.Errorf("%w: genres ожидается списком ID через запятую", ErrInvalidSession)
+		}
+		// как и в списке фильмов: у фильма должны быть все указанные жанры
+		subQuery := db.DB.Table("film_genres").
+			Select("film_id").
+			Where("genre_id IN ?", genres).
+			Group("film_id").
+			Having("COUNT(DISTINCT genre_id) = ?", len(genres))
+		query = query.Where("session.film_id IN (?)", subQuery)
+	}
+	if filter.MaxAge != nil {
+		// поздний сеанс считается сеансом 18+ независимо от рейтинга фильма
+		query = query.Where("GREATEST(film.age_rating, CASE WHEN session.late_show THEN ? ELSE 0 END) <= ?", lateShowAge, *filter.MaxAge)
+	}
+	if filter.PriceMin != nil {
+		query = query.Where(sessionPriceFromExpr+" >= ?", *filter.PriceMin)
+	}
+	if filter.PriceMax != nil {
+		query = query.Where(sessionPriceFromExpr+" <= ?", *filter.PriceMax)
+	}
+
+	query, err := applySessionFilter(query, filter.SessionFilterDTI)
+	if err != nil {
+		return nil, err
+	}
+
+	// запрос переиспользуется для подсчёта и выборки
+	return query.Session(&gorm.Session{}), nil
+}
+
+// окно времени суток [from, to); если from позже to — окно переходит через полночь
+func applyTimeOfDay(query *gorm.DB, fromRaw, toRaw string) (*gorm.DB, error) {
+	if fromRaw == "" {
+		fromRaw = "00:00"
+	}
+	if toRaw == "" {
+		toRaw = "24:00"
+	}
+	fromMin, ok := parseClock(fromRaw)
+	if !ok {
+		return nil, fmt.Errorf("%w: time_from ожидается в формате HH:MM", ErrInvalidSession)
+	}
+	toMin, ok := parseClock(toRaw)
+	if !ok {
+		return nil, fmt.Errorf("%w: time_to ожидается в формате HH:MM", ErrInvalidSession)
+	}
+
+	// время суток — местное для кинотеатра сеанса
+	local := sessionLocalTimeExpr + "::time"
+	fromClock := fmt.Sprintf("%02d:%02d", fromMin/60, fromMin%60)
+	toClock := fmt.Sprintf("%02d:%02d", toMin/60, toMin%60)
+
+	if fromMin <= toMin {
+		return query.Where(local+" >= ? AND "+local+" < ?", fromClock, toClock), nil
+	}
+	return query.Where("("+local+" >= ? OR "+local+" < ?)", fromClock, toClock), nil
+}
+
+// HH:MM в минуты от начала суток; 24:00 допускается как конец суток
+func parseClock(raw string) (int, bool) {
+	if raw == "24:00" {
+		return 24 * 60, true
+	}
+	t, err := time.Parse("15:04", raw)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// список ID через запятую
+func parseIDList(raw string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(raw, ",") {
+		val, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint(val))
+	}
+	return ids, nil
+}
+
+// ORDER BY для поиска; при равенстве — по времени начала и ID, чтобы страницы не перемешивались
+func sessionSearchOrder(sortBy, order string) (string, error) {
+	if sortBy == "" {
+		sortBy = "start_time"
+	}
+	column, ok := sessionSearchSorts[sortBy]
+	if !ok {
+		return "", fmt.Errorf("%w: сортировка возможна по start_time, price или title", ErrInvalidSession)
+	}
+
+	direction := "ASC"
+	switch strings.ToLower(order) {
+	case "", "asc":
+	case "desc":
+		direction = "DESC"
+	default:
+		return "", fmt.Errorf("%w: order ожидается asc или desc", ErrInvalidSession)
+	}
+
+	return column + " " + direction + ", session.start_time ASC, session.id ASC", nil
+}
+
+// сеансы, в которых есть хотя бы n свободных мест подряд в одном ряду; порядок сохраняется
+func filterByAdjacentSeats(tx *gorm.DB, candidates []sessionCandidate, n int) ([]uint, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// Схемы залов — по одному разу на зал
+	hallIDs := make([]uint, 0)
+	seenHall := make(map[uint]bool)
+	sessionIDs := make([]uint, len(candidates))
+	for i, c := range candidates {
+		sessionIDs[i] = c.ID
+		if !seenHall[c.HallID] {
+			seenHall[c.HallID] = true
+			hallIDs = append(hallIDs, c.HallID)
+		}
+	}
+
+	var halls []models.CinemaHall
+	if err := tx.Where("id IN ?", hallIDs).Find(&halls).Error; err != nil {
+		return nil, err
+	}
+	structures := make(map[uint]*HallStructure, len(halls))
+	for _, h := range halls {
+		structure, err := parseHallStructure(h.Structure)
+		if err != nil {
+			continue // зал с повреждённой схемой в поиск не попадает
+		}
+		structures[h.ID] = structure
+	}
+
+	// Занятые места всех сеансов одним запросом
+	var taken []struct {
+		SessionID uint
+		Row       uint
+		Seat      uint
+	}
+	if err := occupiedSeats(tx.Model(&models.Booking{})).
+		Select("booking.session_id, booking.row_num AS row, booking.seat_num AS seat").
+		Where("booking.session_id IN ?", sessionIDs).
+		Find(&taken).Error; err != nil {
+		return nil, err
+	}
+	takenBySession := make(map[uint]map[[2]uint]bool)
+	for _, t := range taken {
+		if takenBySession[t.SessionID] == nil {
+			takenBySession[t.SessionID] = make(map[[2]uint]bool)
+		}
+		takenBySession[t.SessionID][[2]uint{t.Row, t.Seat}] = true
+	}
+
+	result := make([]uint, 0)
+	for _, c := range candidates {
+		structure, ok := structures[c.HallID]
+		if ok && hasAdjacentFreeSeats(structure, takenBySession[c.ID], n) {
+			result = append(result, c.ID)
+		}
+	}
+	return result, nil
+}
+
+// есть ли в каком-нибудь ряду n свободных мест с номерами подряд
+func hasAdjacentFreeSeats(structure *HallStructure, taken map[[2]uint]bool, n int) bool {
+	for _, row := range structure.Rows {
+		seats := make([]HallSeat, len(row.Seats))
+		copy(seats, row.Seats)
+		sort.Slice(seats, func(i, j int) bool { return seats[i].Seat < seats[j].Seat })
+
+		run := 0
+		for i, seat := range seats {
+			if seat.Blocked || taken[[2]uint{row.Row, seat.Seat}] {
+				run = 0
+				continue
+			}
+			if i > 0 && run > 0 && seats[i-1].Seat+1 == seat.Seat {
+				run++
+			} else {
+				run = 1
+			}
+			if run >= n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// карточки найденных сеансов в порядке ids
+func loadSessionSearchItems(tx *gorm.DB, ids []uint) ([]dt.SessionSearchItemDTO, error) {
+	items := make([]dt.SessionSearchItemDTO, 0, len(ids))
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	var sessions []models.Session
+	if err := tx.Preload("Film").Preload("Hall.Cinema").Preload("Hall.HallType").Preload("Prices").
+		Where("id IN ?", ids).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Session, len(sessions))
+	for i := range sessions {
+		byID[sessions[i].ID] = &sessions[i]
+	}
+
+	for _, id := range ids {
+		s, ok := byID[id]
+		if !ok {
+			continue
+		}
+		priceFrom, err := seatPrice(s, models.SeatStandard)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, dt.SessionSearchItemDTO{
+			SessionDTO: toSessionDTO(s),
+			PriceFrom:  priceFrom,
+			FilmTitle:  s.Film.Title,
+			AgeRating:  s.Film.AgeRating,
+			Duration:   s.Film.Duration,
+			CinemaID:   s.Hall.CinemaID,
+			CinemaName: s.Hall.Cinema.Name,
+			HallName:   s.Hall.Name,
+			HallType:   s.Hall.HallType.Name,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,620 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/dt"
+	"CinemaBooking/pkg/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ограничения на размер серии, чтобы случайно не создать расписание на годы вперёд
+const (
+	maxSeriesDays     = 366
+	maxSeriesSessions = 1000
+)
+
+// время начала сеанса в течение дня
+type seriesSlot struct {
+	Hour, Minute int
+}
+
+func (s seriesSlot) String() string {
+	return fmt.Sprintf("%02d:%02d", s.Hour, s.Minute)
+}
+
+func (s seriesSlot) minutes() int {
+	return s.Hour*60 + s.Minute
+}
+
+// разобранный и проверенный шаблон расписания
+type seriesTemplate struct {
+	slots    []seriesSlot // по возрастанию
+	weekdays []int        // 1 — понедельник, 7 — воскресенье, по возрастанию
+	from, to time.Time    // первый и последний день (дата без часового пояса)
+	prices   []models.SessionPrice
+	attrs    models.SessionAttributes
+}
+
+// занятость зала: сеанс и время, до которого зал занят с учётом перерыва
+type busyInterval struct {
+	ID         uint
+	Start, End time.Time
+}
+
+// Предпросмотр серии: какие сеансы будут созданы и с какими существующими они пересекаются
+func PreviewSessionSeries(input dt.CreateSessionSeriesDTI) (*dt.SessionSeriesPreviewDTO, error) {
+	tpl, err := parseSeriesTemplate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var hall models.CinemaHall
+	if err := db.DB.Where("deleted_at IS NULL").First(&hall, input.HallID).Error; err != nil {
+		return nil, errors.New("зал не найден")
+	}
+	film, err := loadScheduleFilm(db.DB, input.FilmID)
+	if err != nil {
+		return nil, err
+	}
+
+	return previewSeries(db.DB, tpl, film, &hall)
+}
+
+// ____________________________________________________ADMIN_ONLY____________________________________________________
+// Получить все действующие серии
+func GetSessionSeriesList() ([]dt.SessionSeriesDTO, error) {
+	var series []models.SessionSeries
+	if err := db.DB.Where("deleted_at IS NULL").Order("id DESC").Find(&series).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dt.SessionSeriesDTO, 0, len(series))
+	for i := range series {
+		result = append(result, toSessionSeriesDTO(&series[i]))
+	}
+	return result, nil
+}
+
+// Получить серию с её сеансами
+func GetSessionSeries(id uint) (*dt.SessionSeriesDTO, error) {
+	var series models.SessionSeries
+	if err := db.DB.Where("deleted_at IS NULL").First(&series, id).Error; err != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var sessions []models.Session
+	if err := db.DB.Preload("Hall.Cinema").
+		Where("series_id = ? AND deleted_at IS NULL", id).
+		Order("start_time ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	result := toSessionSeriesDTO(&series)
+	for i := range sessions {
+		result.Sessions = append(result.Sessions, toSessionDTO(&sessions[i]))
+	}
+	return &result, nil
+}
+
+// Создать серию сеансов одной транзакцией. Если хотя бы один сеанс пересекается
+// с существующими, не создаётся ничего
+func CreateSessionSeries(input dt.CreateSessionSeriesDTI) (*dt.CreateSessionSeriesDTO, error) {
+	tpl, err := parseSeriesTemplate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var result dt.CreateSessionSeriesDTO
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+
+		// 1. Блокируем зал, чтобы расписание не менялось параллельно
+		hall, err := lockHall(tx, input.HallID)
+		if err != nil {
+			return errors.New("зал не найден")
+		}
+		film, err := loadScheduleFilm(tx, input.FilmID)
+		if err != nil {
+			return err
+		}
+
+		// 2. Разворачиваем шаблон и проверяем пересечения
+		preview, err := previewSeries(tx, tpl, film, hall)
+		if err != nil {
+			return err
+		}
+		if preview.Count == 0 {
+			return fmt.Errorf("%w: в заданный период не попадает ни одного сеанса", ErrInvalidSession)
+		}
+		if !preview.OK {
+			return &ScheduleConflictError{SessionIDs: collectConflictIDs(preview.Sessions)}
+		}
+
+		// 3. Сохраняем серию и её сеансы
+		slots := make([]string, len(tpl.slots))
+		for i, s := range tpl.slots {
+			slots[i] = s.String()
+		}
+		slotsJSON, _ := json.Marshal(slots)
+		weekdaysJSON, _ := json.Marshal(tpl.weekdays)
+
+		series := models.SessionSeries{
+			FilmID:   input.FilmID,
+			HallID:   input.HallID,
+			Slots:    datatypes.JSON(slotsJSON),
+			Weekdays: datatypes.JSON(weekdaysJSON),
+			DateFrom: tpl.from,
+			DateTo:   tpl.to,
+			Price:    input.Price,
+
+			SessionAttributes: tpl.attrs,
+		}
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+		surcharge, err := formatSurcharge(tx, tpl.attrs.Format)
+		if err != nil {
+			return err
+		}
+
+		sessions := make([]models.Session, 0, preview.Count)
+		for _, o := range preview.Sessions {
+			sessions = append(sessions, models.Session{
+				FilmID:    input.FilmID,
+				HallID:    input.HallID,
+				StartTime: o.Start.UTC(),
+				Price:     input.Price,
+				Prices:    copyPriceRules(tpl.prices),
+				SeriesID:  &series.ID,
+				Surcharge: surcharge,
+
+				SessionAttributes: tpl.attrs,
+			})
+		}
+		if err := tx.Create(&sessions).Error; err != nil {
+			return err
+		}
+
+		result.ID = series.ID
+		for _, s := range sessions {
+			result.SessionIDs = append(result.SessionIDs, s.ID)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Изменить ещё не начавшиеся сеансы серии: фильм, базовую цену, цены категорий.
+// Фильм нельзя сменить, если на сеансы уже есть брони; новая длительность проверяется на пересечения
+func UpdateSessionSeries(id uint, input dt.UpdateSessionSeriesDTI) (*dt.SeriesUpdateResultDTO, error) {
+	if input.FilmID == nil && input.Price == nil && input.Prices == nil {
+		return nil, errors.New("пустой запрос")
+	}
+	if input.Price != nil && *input.Price <= 0 {
+		return nil, fmt.Errorf("%w: цена должна быть больше нуля", ErrInvalidSession)
+	}
+	var rules []models.SessionPrice
+	if input.Prices != nil {
+		var err error
+		if rules, err = buildPriceRules(input.Prices); err != nil {
+			return nil, err
+		}
+	}
+
+	var result dt.SeriesUpdateResultDTO
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		series, sessions, err := loadFutureSeries(tx, id)
+		if err != nil {
+			return err
+		}
+		ids := sessionIDs(sessions)
+
+		seriesUpdates := make(map[string]interface{})
+		sessionUpdates := make(map[string]interface{})
+		if input.Price != nil {
+			seriesUpdates["price"] = *input.Price
+			sessionUpdates["price"] = *input.Price
+		}
+
+		// смена фильма: только без броней и без пересечений с новой длительностью
+		if input.FilmID != nil && *input.FilmID != series.FilmID {
+			if _, err := loadScheduleFilm(tx, *input.FilmID); err != nil {
+				return err
+			}
+			booked, err := sessionsWithActiveBookings(tx, ids)
+			if err != nil {
+				return err
+			}
+			if len(booked) > 0 {
+				return fmt.Errorf("%w: на сеансы серии уже есть брони, фильм менять нельзя", ErrInvalidSession)
+			}
+
+			for _, s := range sessions {
+				if err := checkHallSchedule(tx, s.HallID, *input.FilmID, s.StartTime, s.ID); err != nil {
+					return err
+				}
+				if err := tx.Model(&models.Session{}).Where("id = ?", s.ID).Update("film_id", *input.FilmID).Error; err != nil {
+					return err
+				}
+			}
+			seriesUpdates["film_id"] = *input.FilmID
+		}
+
+		if len(ids) > 0 && len(sessionUpdates) > 0 {
+			if err := tx.Model(&models.Session{}).Where("id IN ?", ids).Updates(sessionUpdates).Error; err != nil {
+				return err
+			}
+		}
+
+		if input.Prices != nil && len(ids) > 0 {
+			if err := tx.Where("session_id IN ?", ids).Delete(&models.SessionPrice{}).Error; err != nil {
+				return err
+			}
+			for _, sessionID := range ids {
+				copies := copyPriceRules(rules)
+				for i := range copies {
+					copies[i].SessionID = sessionID
+				}
+				if len(copies) > 0 {
+					if err := tx.Create(&copies).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if len(seriesUpdates) > 0 {
+			if err := tx.Model(series).Updates(seriesUpdates).Error; err != nil {
+				return err
+			}
+		}
+
+		result.Updated = len(ids)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Удалить ещё не начавшиеся сеансы серии. Сеансы с действующими бронями остаются
+// и возвращаются в kept_ids; если таких нет, серия закрывается
+func DeleteSessionSeries(id uint) (*dt.SeriesDeleteResultDTO, error) {
+	result := dt.SeriesDeleteResultDTO{KeptIDs: make([]uint, 0)}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		series, sessions, err := loadFutureSeries(tx, id)
+		if err != nil {
+			return err
+		}
+
+		booked, err := sessionsWithActiveBookings(tx, sessionIDs(sessions))
+		if err != nil {
+			return err
+		}
+
+		var remove []uint
+		for _, s := range sessions {
+			if booked[s.ID] {
+				result.KeptIDs = append(result.KeptIDs, s.ID)
+			} else {
+				remove = append(remove, s.ID)
+			}
+		}
+
+		// сеансы помечаются удалёнными: на них могут ссылаться отменённые брони
+		now := time.Now()
+		if len(remove) > 0 {
+			if err := tx.Model(&models.Session{}).
+				Where("id IN ?", remove).
+				Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+		}
+		if len(result.KeptIDs) == 0 {
+			if err := tx.Model(series).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+		}
+
+		result.Deleted = len(remove)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// разбор шаблона: слоты, дни недели, период и цены
+func parseSeriesTemplate(input dt.CreateSessionSeriesDTI) (*seriesTemplate, error) {
+	if input.Price <= 0 {
+		return nil, fmt.Errorf("%w: цена должна быть больше нуля", ErrInvalidSession)
+	}
+
+	tpl := &seriesTemplate{}
+
+	seenSlots := make(map[int]bool)
+	for _, raw := range input.Slots {
+		t, err := time.Parse("15:04", raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: время %q ожидается в формате HH:MM", ErrInvalidSession, raw)
+		}
+		slot := seriesSlot{Hour: t.Hour(), Minute: t.Minute()}
+		if seenSlots[slot.minutes()] {
+			return nil, fmt.Errorf("%w: время %s указано дважды", ErrInvalidSession, slot)
+		}
+		seenSlots[slot.minutes()] = true
+		tpl.slots = append(tpl.slots, slot)
+	}
+	sort.Slice(tpl.slots, func(i, j int) bool {
+		return tpl.slots[i].minutes() < tpl.slots[j].minutes()
+	})
+
+	seenDays := make(map[int]bool)
+	for _, d := range input.Weekdays {
+		if d < 1 || d > 7 {
+			return nil, fmt.Errorf("%w: день недели должен быть от 1 до 7", ErrInvalidSession)
+		}
+		if !seenDays[d] {
+			seenDays[d] = true
+			tpl.weekdays = append(tpl.weekdays, d)
+		}
+	}
+	sort.Ints(tpl.weekdays)
+
+	var err error
+	if tpl.from, err = time.Parse("2006-01-02", input.DateFrom); err != nil {
+		return nil, fmt.Errorf("%w: date_from ожидается в формате YYYY-MM-DD", ErrInvalidSession)
+	}
+	if tpl.to, err = time.Parse("2006-01-02", input.DateTo); err != nil {
+		return nil, fmt.Errorf("%w: date_to ожидается в формате YYYY-MM-DD", ErrInvalidSession)
+	}
+	if tpl.to.Before(tpl.from) {
+		return nil, fmt.Errorf("%w: date_to раньше date_from", ErrInvalidSession)
+	}
+	if tpl.to.Sub(tpl.from) > maxSeriesDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: период серии не может быть больше %d дней", ErrInvalidSession, maxSeriesDays)
+	}
+
+	if tpl.prices, err = buildPriceRules(input.Prices); err != nil {
+		return nil, err
+	}
+	if tpl.attrs, err = buildSessionAttributes(input.SessionAttributesDTI); err != nil {
+		return nil, err
+	}
+
+	return tpl, nil
+}
+
+// сеансы по шаблону; слоты — местное время кинотеатра, прошедшие пропускаются. Соседние слоты
+// (и последний слот с первым следующего дня) не должны пересекаться с учётом длительности фильма и перерыва зала
+func (tpl *seriesTemplate) expand(film *models.Film, hall *models.CinemaHall, loc *time.Location) ([]busyInterval, error) {
+	length := time.Duration(film.Duration)*time.Minute + hallBuffer(hall)
+
+	for i := range tpl.slots {
+		next := tpl.slots[(i+1)%len(tpl.slots)]
+		gap := time.Duration(next.minutes()-tpl.slots[i].minutes()) * time.Minute
+		if gap <= 0 {
+			gap += 24 * time.Hour
+		}
+		if gap < length {
+			return nil, fmt.Errorf("%w: сеансы в %s и %s пересекаются с учётом длительности фильма и перерыва",
+				ErrInvalidSession, tpl.slots[i], next)
+		}
+	}
+
+	days := make(map[time.Weekday]bool)
+	for _, d := range tpl.weekdays {
+		days[time.Weekday(d%7)] = true
+	}
+
+	now := time.Now()
+	var result []busyInterval
+	for day := tpl.from; !day.After(tpl.to); day = day.AddDate(0, 0, 1) {
+		if !days[day.Weekday()] {
+			continue
+		}
+		for _, slot := range tpl.slots {
+			start, exists := localTime(time.Date(day.Year(), day.Month(), day.Day(), slot.Hour, slot.Minute, 0, 0, time.UTC), loc)
+			if !start.After(now) {
+				continue
+			}
+			// в ночь перевода часов вперёд слот может попасть в пропущенный час — такой сеанс не переносим молча
+			if !exists {
+				return nil, fmt.Errorf("%w: местного времени %s %s в поясе %s нет — в это время часы переводятся вперёд",
+					ErrInvalidSession, day.Format("2006-01-02"), slot, loc)
+			}
+			// промежутки между слотами выше проверены по часам на стене, а в ночь перевода часов сутки короче
+			if n := len(result); n > 0 && start.Before(result[n-1].End) {
+				return nil, fmt.Errorf("%w: сеансы %s и %s пересекаются из-за перевода часов",
+					ErrInvalidSession, result[n-1].Start.In(loc).Format("2006-01-02 15:04"), start.Format("2006-01-02 15:04"))
+			}
+			result = append(result, busyInterval{Start: start, End: start.Add(length)})
+			if len(result) > maxSeriesSessions {
+				return nil, fmt.Errorf("%w: в серии больше %d сеансов", ErrInvalidSession, maxSeriesSessions)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// развернуть шаблон и сверить каждый сеанс с уже занятым временем зала
+func previewSeries(tx *gorm.DB, tpl *seriesTemplate, film *models.Film, hall *models.CinemaHall) (*dt.SessionSeriesPreviewDTO, error) {
+	loc, err := hallLocation(tx, hall.ID)
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := tpl.expand(film, hall, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dt.SessionSeriesPreviewDTO{OK: true, Count: len(occurrences), Sessions: make([]dt.SeriesOccurrenceDTO, 0, len(occurrences))}
+	if len(occurrences) == 0 {
+		return result, nil
+	}
+
+	busy, err := loadHallTimeline(tx, hall, occurrences[0].Start, occurrences[len(occurrences)-1].End)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range occurrences {
+		item := dt.SeriesOccurrenceDTO{Start: o.Start.In(loc), End: o.End.In(loc), ConflictIDs: make([]uint, 0)}
+		for _, b := range busy {
+			if b.Start.Before(o.End) && o.Start.Before(b.End) {
+				item.ConflictIDs = append(item.ConflictIDs, b.ID)
+			}
+		}
+		if len(item.ConflictIDs) > 0 {
+			result.OK = false
+		}
+		result.Sessions = append(result.Sessions, item)
+	}
+
+	return result, nil
+}
+
+// сеансы зала, которые могут пересечься с периодом [from, to)
+func loadHallTimeline(tx *gorm.DB, hall *models.CinemaHall, from, to time.Time) ([]busyInterval, error) {
+	var rows []struct {
+		ID        uint
+		StartTime time.Time
+		Duration  uint
+	}
+	// фильм длиннее суток не предполагается, поэтому берём сеансы, начавшиеся не раньше чем за сутки
+	if err := tx.Model(&models.Session{}).
+		Select("session.id, session.start_time, COALESCE(film.duration, 0) AS duration").
+		Joins("JOIN film ON film.id = session.film_id").
+		Where("session.hall_id = ? AND session.deleted_at IS NULL", hall.ID).
+		Where("session.status <> ?", models.SessionCanceled).
+		Where("session.start_time >= ? AND session.start_time < ?", from.Add(-24*time.Hour), to).
+		Order("session.start_time ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	buffer := hallBuffer(hall)
+	result := make([]busyInterval, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, busyInterval{
+			ID:    r.ID,
+			Start: r.StartTime,
+			End:   r.StartTime.Add(time.Duration(r.Duration)*time.Minute + buffer),
+		})
+	}
+	return result, nil
+}
+
+// серия и её ещё не начавшиеся сеансы, заблокированные на изменение: покупка берёт сеанс
+// на чтение (loadBookableSession), поэтому проверка броней и правка сеансов не разойдутся с новой бронью
+func loadFutureSeries(tx *gorm.DB, id uint) (*models.SessionSeries, []models.Session, error) {
+	var series models.SessionSeries
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at IS NULL").
+		First(&series, id).Error; err != nil {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+
+	var sessions []models.Session
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("series_id = ? AND start_time > ? AND deleted_at IS NULL", id, time.Now()).
+		Where("status <> ?", models.SessionCanceled).
+		Order("start_time ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, nil, err
+	}
+	return &series, sessions, nil
+}
+
+// сеансы из списка, на которые есть действующие брони
+func sessionsWithActiveBookings(tx *gorm.DB, ids []uint) (map[uint]bool, error) {
+	result := make(map[uint]bool)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var booked []uint
+	if err := occupiedSeats(tx.Model(&models.Booking{})).
+		Where("booking.session_id IN ?", ids).
+		Distinct("booking.session_id").
+		Pluck("booking.session_id", &booked).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range booked {
+		result[id] = true
+	}
+	return result, nil
+}
+
+func sessionIDs(sessions []models.Session) []uint {
+	ids := make([]uint, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+// уникальные ID пересекающихся сеансов по порядку
+func collectConflictIDs(occurrences []dt.SeriesOccurrenceDTO) []uint {
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, o := range occurrences {
+		for _, id := range o.ConflictIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// копия правил цен для нового сеанса (у каждого сеанса свои строки)
+func copyPriceRules(rules []models.SessionPrice) []models.SessionPrice {
+	result := make([]models.SessionPrice, len(rules))
+	for i, r := range rules {
+		result[i] = models.SessionPrice{
+			SeatType:     r.SeatType,
+			Price:        r.Price,
+			MultiplierBP: r.MultiplierBP,
+		}
+	}
+	return result
+}
+
+func toSessionSeriesDTO(s *models.SessionSeries) dt.SessionSeriesDTO {
+	result := dt.SessionSeriesDTO{
+		ID:       s.ID,
+		FilmID:   s.FilmID,
+		HallID:   s.HallID,
+		DateFrom: s.DateFrom.Format("2006-01-02"),
+		DateTo:   s.DateTo.Format("2006-01-02"),
+		Price:    s.Price,
+
+		SessionAttributesDTO: toSessionAttributesDTO(s.SessionAttributes),
+	}
+	_ = json.Unmarshal(s.Slots, &result.Slots)
+	_ = json.Unmarshal(s.Weekdays, &result.Weekdays)
+	return result
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"CinemaBooking/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+// шаблон на дни [from, from+days) по всем дням недели
+func testSeriesTemplate(from time.Time, days int, slots ...seriesSlot) *seriesTemplate {
+	return &seriesTemplate{
+		slots:    slots,
+		weekdays: []int{1, 2, 3, 4, 5, 6, 7},
+		from:     from,
+		to:       from.AddDate(0, 0, days-1),
+	}
+}
+
+// фильм и зал без перерыва: длина сеанса равна длительности фильма
+func testSeriesFilmHall(length time.Duration) (*models.Film, *models.CinemaHall) {
+	buffer := uint(0)
+	return &models.Film{Duration: uint(length / time.Minute)}, &models.CinemaHall{BufferMinutes: &buffer}
+}
+
+// первый день перевода часов вперёд в марте следующего года
+func springForwardDay(t *testing.T, loc *time.Location) time.Time {
+	t.Helper()
+	year := time.Now().Year() + 1
+	for d := 2; d <= 31; d++ {
+		_, before := time.Date(year, time.March, d-1, 12, 0, 0, 0, loc).Zone()
+		_, after := time.Date(year, time.March, d, 12, 0, 0, 0, loc).Zone()
+		if after > before {
+			return time.Date(year, time.March, d, 0, 0, 0, 0, time.UTC)
+		}
+	}
+	t.Fatalf("в марте %d в поясе %s нет перевода часов", year, loc)
+	return time.Time{}
+}
+
+func TestSeriesExpandGaps(t *testing.T) {
+	moscow := mustLocation(t, "Europe/Moscow")
+	newYork := mustLocation(t, "America/New_York")
+	from := startOfDay(time.Now().AddDate(0, 0, 7), time.UTC)
+	dst := springForwardDay(t, newYork)
+
+	tests := []struct {
+		name   string
+		from   time.Time
+		days   int
+		loc    *time.Location
+		length time.Duration
+		slots  []seriesSlot
+		ok     bool
+	}{
+		{"один слот: ровно сутки", from, 3, moscow, 24 * time.Hour, []seriesSlot{{10, 0}}, true},
+		{"один слот: больше суток", from, 3, moscow, 24*time.Hour + time.Minute, []seriesSlot{{10, 0}}, false},
+		{"соседние слоты впритык", from, 2, moscow, 4 * time.Hour, []seriesSlot{{9, 0}, {13, 0}}, true},
+		{"соседние слоты пересекаются", from, 2, moscow, 4*time.Hour + time.Minute, []seriesSlot{{9, 0}, {13, 0}}, false},
+		// 23:00 → 10:00 следующего дня: 11 часов через полночь
+		{"через полночь впритык", from, 2, moscow, 11 * time.Hour, []seriesSlot{{10, 0}, {23, 0}}, true},
+		{"через полночь пересекаются", from, 2, moscow, 11*time.Hour + time.Minute, []seriesSlot{{10, 0}, {23, 0}}, false},
+		{"поздний слот до полуночи", from, 2, moscow, 90 * time.Minute, []seriesSlot{{0, 30}, {22, 30}}, true},
+		{"поздний слот после полуночи", from, 2, moscow, 2*time.Hour + time.Minute, []seriesSlot{{0, 30}, {22, 30}}, false},
+		// в ночь перевода часов вперёд от 23:00 до 10:00 проходит 10 часов, а не 11
+		{"через полночь перевода часов", dst.AddDate(0, 0, -1), 2, newYork, 11 * time.Hour, []seriesSlot{{10, 0}, {23, 0}}, false},
+		{"через полночь после перевода часов", dst.AddDate(0, 0, 1), 2, newYork, 11 * time.Hour, []seriesSlot{{10, 0}, {23, 0}}, true},
+		// слот в пропущенный при переводе часов час не сдвигается молча, а отклоняется
+		{"слот в пропущенный час", dst.AddDate(0, 0, -1), 3, newYork, time.Hour, []seriesSlot{{2, 30}}, false},
+		{"слот сразу после пропущенного часа", dst.AddDate(0, 0, -1), 3, newYork, time.Hour, []seriesSlot{{3, 0}}, true},
+	}
+
+	for _, tt := range tests {
+		film, hall := testSeriesFilmHall(tt.length)
+		got, err := testSeriesTemplate(tt.from, tt.days, tt.slots...).expand(film, hall, tt.loc)
+		if !tt.ok {
+			if !errors.Is(err, ErrInvalidSession) {
+				t.Errorf("%s: ожидалась ErrInvalidSession, получено %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if want := tt.days * len(tt.slots); len(got) != want {
+			t.Errorf("%s: сеансов %d, ожидалось %d", tt.name, len(got), want)
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i].Start.Before(got[i-1].End) {
+				t.Errorf("%s: сеансы %s и %s пересекаются", tt.name, got[i-1].Start, got[i].Start)
+			}
+		}
+	}
+}
+
+func TestSeriesExpandSchedule(t *testing.T) {
+	moscow := mustLocation(t, "Europe/Moscow")
+	from := startOfDay(time.Now().AddDate(0, 0, 7), time.UTC)
+	for from.Weekday() != time.Monday {
+		from = from.AddDate(0, 0, 1)
+	}
+
+	tpl := testSeriesTemplate(from, 14, seriesSlot{12, 0}, seriesSlot{19, 30})
+	tpl.weekdays = []int{1, 7} // понедельник и воскресенье
+	film, hall := testSeriesFilmHall(2 * time.Hour)
+
+	got, err := tpl.expand(film, hall, moscow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 8 { // два понедельника и два воскресенья по два сеанса
+		t.Fatalf("сеансов %d, ожидалось 8", len(got))
+	}
+	for _, s := range got {
+		local := s.Start.In(moscow)
+		if wd := local.Weekday(); wd != time.Monday && wd != time.Sunday {
+			t.Errorf("сеанс %s в %s", local, wd)
+		}
+		if hm := local.Format("15:04"); hm != "12:00" && hm != "19:30" {
+			t.Errorf("сеанс %s не в слот", local)
+		}
+		if s.End.Sub(s.Start) != 2*time.Hour {
+			t.Errorf("сеанс %s длится %s", local, s.End.Sub(s.Start))
+		}
+	}
+
+	// прошедшие слоты пропускаются
+	past := testSeriesTemplate(startOfDay(time.Now().AddDate(0, 0, -3), time.UTC), 2, seriesSlot{12, 0})
+	if got, err := past.expand(film, hall, moscow); err != nil || len(got) != 0 {
+		t.Errorf("прошедшие дни: %d сеансов, %v", len(got), err)
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"CinemaBooking/pkg/db"
+	"CinemaBooking/pkg/models"
+	"CinemaBooking/pkg/money"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// DSN тестовой базы PostgreSQL; без него тесты с базой пропускаются.
+// База мигрируется как при запуске сервиса, данные тестов в ней остаются
+const testDSNEnv = "TEST_DB_DSN"
+
+var (
+	testDBOnce sync.Once
+	testDBErr  error
+)
+
+// подключить db.DB к тестовой базе или пропустить тест
+func openTestDB(t *testing.T) {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s не задан — тест с базой пропущен", testDSNEnv)
+	}
+
+	testDBOnce.Do(func() {
+		g, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+			NamingStrategy: schema.NamingStrategy{SingularTable: true},
+			TranslateError: true,
+			Logger:         logger.Default.LogMode(logger.Silent),
+		})
+		if err != nil {
+			testDBErr = err
+			return
+		}
+		if err := db.Migrate(g); err != nil {
+			testDBErr = err
+			return
+		}
+		db.DB = g
+	})
+	if testDBErr != nil {
+		t.Fatalf("тестовая база: %v", testDBErr)
+	}
+}
+
+// транзакция теста, которая откатывается после него: изменения видит только сам тест
+func testTx(t *testing.T) *gorm.DB {
+	t.Helper()
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		t.Fatalf("транзакция теста: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// случайная строка из цифр: логины и телефоны пользователей уникальны
+func randomDigits(t *testing.T, n int) string {
+	t.Helper()
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	v, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%0*d", n, v)
+}
+
+// покупатель с балансом: остаток заводится проводкой, чтобы кэш в профиле совпадал с журналом
+func newTestUser(t *testing.T, balance money.Money) uint {
+	t.Helper()
+	phone := randomDigits(t, 11)
+	user := models.User{
+		Auth:     models.AuthCredential{Login: "test_" + phone, PasswordHash: []byte("-")},
+		Profile:  models.Profile{FirstName: "Тест", Phone: phone},
+		UserType: models.Customer,
+	}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("пользователь: %v", err)
+	}
+
+	if balance > 0 {
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			return post(tx, models.LedgerEntry{
+				Operation:   models.LedgerOpening,
+				Description: "Тестовый остаток",
+				UserID:      user.ID,
+			},
+				posting{models.AccountOpening, 0, -balance},
+				posting{models.AccountWallet, user.ID, balance},
+			)
+		})
+		if err != nil {
+			t.Fatalf("баланс пользователя: %v", err)
+		}
+	}
+	return user.ID
+}
+
+// сеанс через два дня в новом кинотеатре и зале нового типа, rows×seats мест
+func newTestSession(t *testing.T, rows, seats uint, price money.Money) uint {
+	t.Helper()
+	var layout []string
+	for r := uint(1); r <= rows; r++ {
+		layout = append(layout, fmt.Sprintf(`{"row":%d,"seats":%d}`, r, seats))
+	}
+	hall := models.CinemaHall{
+		Cinema:    models.Cinema{Name: "Тестовый кинотеатр", Timezone: "Europe/Moscow", AgeCheck: models.AgeCheckBlock},
+		HallType:  models.HallType{Name: "Тестовый"},
+		Name:      "Зал " + randomDigits(t, 4),
+		Capacity:  rows * seats,
+		Structure: []byte(`{"rows":[` + strings.Join(layout, ",") + `]}`),
+	}
+	if err := db.DB.Create(&hall).Error; err != nil {
+		t.Fatalf("зал: %v", err)
+	}
+
+	session := models.Session{
+		Film:      models.Film{Title: "Тестовый фильм", Duration: 100},
+		HallID:    hall.ID,
+		StartTime: time.Now().Add(48 * time.Hour).UTC().Truncate(time.Minute),
+		Price:     price,
+		Status:    models.SessionScheduled,
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		t.Fatalf("сеанс: %v", err)
+	}
+	return session.ID
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"CinemaBooking/pkg/models"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// часовой пояс кинотеатра, если он не задан
+const defaultTimezone = "Europe/Moscow"
+
+// местное время начала сеанса в поясе его кинотеатра (в запросе нужны JOIN cinema_hall и cinema)
+const sessionLocalTimeExpr = "(session.start_time AT TIME ZONE cinema.timezone)"
+
+// загруженные часовые пояса: LoadLocation каждый раз читает базу tzdata
+var locations sync.Map
+
+// форматы времени, которые принимаются от администратора
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ____________________________________________________INTERNAL____________________________________________________
+// часовой пояс по имени IANA (Asia/Novosibirsk); пустое имя — пояс по умолчанию
+func loadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultTimezone
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("%w %q", ErrInvalidTimezone, name)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// часовой пояс кинотеатра; испорченное значение в базе не должно ломать выдачу — берём пояс по умолчанию
+func cinemaLocation(cinema *models.Cinema) *time.Location {
+	if loc, err := loadLocation(cinema.Timezone); err == nil {
+		return loc
+	}
+	loc, err := loadLocation(defaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// часовой пояс сеанса (зал и кинотеатр сеанса должны быть загружены)
+func sessionLocation(session *models.Session) *time.Location {
+	return cinemaLocation(&session.Hall.Cinema)
+}
+
+// часовой пояс кинотеатра, которому принадлежит зал
+func hallLocation(tx *gorm.DB, hallID uint) (*time.Location, error) {
+	var cinema models.Cinema
+	if err := tx.Joins("JOIN cinema_hall ON cinema_hall.cinema_id = cinema.id").
+		Where("cinema_hall.id = ?", hallID).
+		First(&cinema).Error; err != nil {
+		return nil, errors.New("зал не найден")
+	}
+	return cinemaLocation(&cinema), nil
+}
+
+// время от администратора: со смещением (RFC3339) — как есть, без смещения — местное время кинотеатра.
+// Результат всегда в UTC
+func parseLocalTime(raw string, loc *time.Location) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range localTimeLayouts {
+		if wall, err := time.Parse(layout, raw); err == nil {
+			t, ok := localTime(wall, loc)
+			if !ok {
+				return time.Time{}, fmt.Errorf("%w: местного времени %s в поясе %s нет — в это время часы переводятся вперёд", ErrInvalidSession, raw, loc)
+			}
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: время ожидается в формате RFC3339 или YYYY-MM-DDTHH:MM (местное время кинотеатра)", ErrInvalidSession)
+}
+
+// момент, когда часы в поясе loc показывают wall (дата и время wall без учёта её пояса).
+// false — такого местного времени нет: при переводе часов вперёд Go молча сдвинул бы его на час
+func localTime(wall time.Time, loc *time.Location) (time.Time, bool) {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+	shown := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return t, shown.Equal(time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC))
+}
+
+// полночь местных суток, в которые попадает момент t
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
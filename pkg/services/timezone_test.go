@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := loadLocation(name)
+	if err != nil {
+		t.Fatalf("часовой пояс %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseLocalTime(t *testing.T) {
+	moscow := mustLocation(t, "Europe/Moscow")
+	newYork := mustLocation(t, "America/New_York")
+	berlin := mustLocation(t, "Europe/Berlin")
+
+	tests := []struct {
+		name string
+		raw  string
+		loc  *time.Location
+		want string // UTC, RFC3339; пусто — ожидается ошибка
+	}{
+		{"местное время Москвы", "2024-06-01T19:30", moscow, "2024-06-01T16:30:00Z"},
+		{"с секундами и пробелом", "2024-06-01 19:30:15", moscow, "2024-06-01T16:30:15Z"},
+		{"смещение важнее пояса", "2024-06-01T19:30:00+05:00", moscow, "2024-06-01T14:30:00Z"},
+		{"переход через полночь UTC", "2024-06-01T01:00", moscow, "2024-05-31T22:00:00Z"},
+
+		{"Нью-Йорк зимой", "2024-03-10T01:59", newYork, "2024-03-10T06:59:00Z"},
+		{"Нью-Йорк: пропущенный час", "2024-03-10T02:30", newYork, ""},
+		{"Нью-Йорк: начало пропущенного часа", "2024-03-10T02:00", newYork, ""},
+		{"Нью-Йорк летом", "2024-03-10T03:00", newYork, "2024-03-10T07:00:00Z"},
+		// осенью час повторяется: берётся первое (летнее) время
+		{"Нью-Йорк: повторный час", "2024-11-03T01:30", newYork, "2024-11-03T05:30:00Z"},
+
+		{"Берлин: пропущенный час", "2024-03-31T02:15", berlin, ""},
+		{"Берлин после перевода", "2024-03-31T03:15", berlin, "2024-03-31T01:15:00Z"},
+		{"Берлин: со смещением в пропущенный час", "2024-03-31T02:15:00+01:00", berlin, "2024-03-31T01:15:00Z"},
+
+		{"пусто", "", moscow, ""},
+		{"только дата", "2024-06-01", moscow, ""},
+		{"мусор", "завтра вечером", moscow, ""},
+		{"несуществующая дата", "2024-02-30T10:00", moscow, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := parseLocalTime(tt.raw, tt.loc)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidSession) {
+				t.Errorf("%s: %q → %v, %v; ожидалась ErrInvalidSession", tt.name, tt.raw, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %q: %v", tt.name, tt.raw, err)
+			continue
+		}
+		if got.Location() != time.UTC || got.Format(time.RFC3339) != tt.want {
+			t.Errorf("%s: %q → %s, ожидалось %s", tt.name, tt.raw, got.Format(time.RFC3339), tt.want)
+		}
+	}
+}